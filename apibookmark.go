@@ -0,0 +1,203 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// bookmarkTable keeps the bookmark names and ids used in a file
+// so that new and copied bookmarks won't conflict with them
+type bookmarkTable struct {
+	names  map[string]struct{}
+	nextID int
+	// copied maps the names and ids of bookmarks copied from other files
+	copiednames map[*Docx]map[string]string
+	copiedids   map[*Docx]map[int]int
+}
+
+// bookmarkTable loads the bookmarks in the document body on the first call
+func (f *Docx) bookmarkTable() *bookmarkTable {
+	if f.bookmarks != nil {
+		return f.bookmarks
+	}
+	bt := &bookmarkTable{
+		names:       make(map[string]struct{}, 16),
+		copiednames: make(map[*Docx]map[string]string, 4),
+		copiedids:   make(map[*Docx]map[int]int, 4),
+	}
+	for _, b := range f.Bookmarks() {
+		bt.names[b.Name] = struct{}{}
+		if b.ID >= bt.nextID {
+			bt.nextID = b.ID + 1
+		}
+	}
+	f.bookmarks = bt
+	return bt
+}
+
+// newName returns name, or name with a suffix like _1 if name is used
+func (bt *bookmarkTable) newName(name string) string {
+	n := name
+	for i := 1; ; i++ {
+		if _, ok := bt.names[n]; !ok {
+			break
+		}
+		n = name + "_" + strconv.Itoa(i)
+	}
+	bt.names[n] = struct{}{}
+	return n
+}
+
+// newID returns an unused bookmark id
+func (bt *bookmarkTable) newID() int {
+	bt.nextID++
+	return bt.nextID - 1
+}
+
+// Bookmarks returns the starts of all bookmarks in the document body
+func (f *Docx) Bookmarks() []*BookmarkStart {
+	bs := make([]*BookmarkStart, 0, 16)
+	for _, it := range f.Document.Body.Items {
+		if b, ok := it.(*BookmarkStart); ok {
+			bs = append(bs, b)
+		}
+	}
+	walkParagraphs(f.Document.Body.Items, func(p *Paragraph) bool {
+		for _, c := range p.Children {
+			if b, ok := c.(*BookmarkStart); ok {
+				bs = append(bs, b)
+			}
+		}
+		return true
+	})
+	return bs
+}
+
+// AddBookmark marks the whole content of p as the bookmark of name
+// and returns its start. A suffix like _1 will be added to name if
+// there's already a bookmark of name in the file.
+func (p *Paragraph) AddBookmark(name string) *BookmarkStart {
+	bt := p.file.bookmarkTable()
+	b := &BookmarkStart{ID: bt.newID(), Name: bt.newName(name)}
+	p.Children = append(append(make([]interface{}, 0, len(p.Children)+2), b), p.Children...)
+	p.Children = append(p.Children, &BookmarkEnd{ID: b.ID})
+	return b
+}
+
+// AddAnchorLink adds a hyperlink to the bookmark of anchor in the document
+func (p *Paragraph) AddAnchorLink(text string, anchor string) *Hyperlink {
+	hyperlink := &Hyperlink{
+		Anchor: anchor,
+		Run: Run{
+			RunProperties: &RunProperties{
+				RunStyle: &RunStyle{
+					Val: HYPERLINK_STYLE,
+				},
+			},
+			InstrText: text,
+		},
+	}
+
+	p.Children = append(p.Children, hyperlink)
+
+	return hyperlink
+}
+
+// copiedBookmarkName returns the name in f of the bookmark of name copied from,
+// which is unique in f
+func (f *Docx) copiedBookmarkName(from *Docx, name string) string {
+	bt := f.bookmarkTable()
+	m, ok := bt.copiednames[from]
+	if !ok {
+		m = make(map[string]string, 16)
+		bt.copiednames[from] = m
+	}
+	n, ok := m[name]
+	if !ok {
+		n = bt.newName(name)
+		m[name] = n
+	}
+	return n
+}
+
+// copiedBookmarkID returns the id in f of the bookmark of id copied from
+func (f *Docx) copiedBookmarkID(from *Docx, id int) int {
+	bt := f.bookmarkTable()
+	m, ok := bt.copiedids[from]
+	if !ok {
+		m = make(map[int]int, 16)
+		bt.copiedids[from] = m
+	}
+	n, ok := m[id]
+	if !ok {
+		n = bt.newID()
+		m[id] = n
+	}
+	return n
+}
+
+// copybookmark copies the bookmark start or end o from the file from to f,
+// and returns o itself if it is not a bookmark
+func (f *Docx) copybookmark(from *Docx, o interface{}) interface{} {
+	if from == nil || from == f {
+		return o
+	}
+	switch b := o.(type) {
+	case *BookmarkStart:
+		nb := *b
+		nb.ID = f.copiedBookmarkID(from, b.ID)
+		nb.Name = f.copiedBookmarkName(from, b.Name)
+		return &nb
+	case *BookmarkEnd:
+		return &BookmarkEnd{ID: f.copiedBookmarkID(from, b.ID)}
+	}
+	return o
+}
+
+// copiedFieldInstr renames the bookmark referred in the field
+// instruction like REF, PAGEREF and NOTEREF copied from
+func (f *Docx) copiedFieldInstr(from *Docx, instr string) string {
+	if from == nil || from == f {
+		return instr
+	}
+	return renamedFieldInstr(instr, func(name string) string {
+		return f.copiedBookmarkName(from, name)
+	})
+}
+
+// renamedFieldInstr renames the bookmark referred in the field
+// instruction like REF, PAGEREF and NOTEREF by rename
+func renamedFieldInstr(instr string, rename func(name string) string) string {
+	fields := strings.Fields(instr)
+	if len(fields) < 2 {
+		return instr
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "REF", "PAGEREF", "NOTEREF":
+		// search after the field name, which may contain the bookmark name
+		i := strings.Index(instr, fields[0]) + len(fields[0])
+		i += strings.Index(instr[i:], fields[1])
+		return instr[:i] + rename(fields[1]) + instr[i+len(fields[1]):]
+	}
+	return instr
+}
@@ -0,0 +1,428 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRunNotFound the run is not in the document body
+	ErrRunNotFound = errors.New("run not found in document")
+	// ErrInvalidRunRange the end run is before the start run
+	ErrInvalidRunRange = errors.New("invalid run range")
+)
+
+func (f *Docx) newComments(name string) *Comments {
+	return &Comments{
+		XMLW:     XMLNS_W,
+		XMLR:     XMLNS_R,
+		XMLWP:    XMLNS_WP,
+		XMLWPS:   XMLNS_WPS,
+		XMLWPC:   XMLNS_WPC,
+		XMLWPG:   XMLNS_WPG,
+		XMLW14:   XMLNS_W14,
+		Comments: make([]*Comment, 0, 8),
+		name:     name,
+		file:     f,
+	}
+}
+
+// Comments returns word/comments.xml, or nil if there's no comment
+func (f *Docx) Comments() *Comments {
+	return f.comments
+}
+
+// Comment gets the comment (or nil on notfound) by id
+func (cs *Comments) Comment(id int) *Comment {
+	if cs == nil {
+		return nil
+	}
+	for _, c := range cs.Comments {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// Replies returns the comments replying c
+func (cs *Comments) Replies(c *Comment) []*Comment {
+	if cs == nil {
+		return nil
+	}
+	var replies []*Comment
+	for _, x := range cs.Comments {
+		if x.Parent == c {
+			replies = append(replies, x)
+		}
+	}
+	return replies
+}
+
+// addComment makes word/comments.xml if not exist and adds a comment of text
+func (f *Docx) addComment(author, text string) *Comment {
+	if f.comments == nil {
+		f.comments = f.newComments("comments.xml")
+		f.addPartRelation(REL_COMMENTS, f.comments.name)
+	}
+	c := &Comment{
+		Author: author,
+		Date:   time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Items:  make([]interface{}, 0, 4),
+		file:   f,
+	}
+	for _, x := range f.comments.Comments {
+		if x.ID >= c.ID {
+			c.ID = x.ID + 1
+		}
+	}
+	p := c.AddParagraph()
+	p.Children = append(p.Children, &Run{Children: []interface{}{&AnnotationRef{}}})
+	p.AddText(text)
+	f.comments.Comments = append(f.comments.Comments, c)
+	return c
+}
+
+// walkParagraphs calls fn on each paragraph in items, including those
+// in table cells and nested tables, until fn returns false
+func walkParagraphs(items []interface{}, fn func(p *Paragraph) bool) bool {
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			if !fn(o) {
+				return false
+			}
+		case *Table:
+			for _, tr := range o.TableRows {
+				for _, tc := range tr.TableCells {
+					if !walkParagraphs(tc.Items, fn) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// insertChild inserts x into the children of p at i
+func (p *Paragraph) insertChild(i int, x interface{}) {
+	p.Children = append(p.Children, nil)
+	copy(p.Children[i+1:], p.Children[i:])
+	p.Children[i] = x
+}
+
+// AddComment adds a comment of text by author on the runs
+// from the run from to the run to (both included) in the document body.
+func (f *Docx) AddComment(from, to *Run, author, text string) (*Comment, error) {
+	var pfrom, pto *Paragraph
+	walkParagraphs(f.Document.Body.Items, func(p *Paragraph) bool {
+		for _, c := range p.Children {
+			if c == from && pfrom == nil {
+				pfrom = p
+			}
+			if c == to && pfrom != nil {
+				pto = p
+				return false
+			}
+		}
+		return true
+	})
+	if pfrom == nil {
+		return nil, ErrRunNotFound
+	}
+	if pto == nil {
+		return nil, ErrInvalidRunRange
+	}
+	c := f.addComment(author, text)
+	for i, x := range pfrom.Children {
+		if x == from {
+			pfrom.insertChild(i, &CommentRangeStart{ID: c.ID})
+			break
+		}
+	}
+	for i, x := range pto.Children {
+		if x == to {
+			pto.insertChild(i+1, &CommentRangeEnd{ID: c.ID})
+			pto.insertChild(i+2, &Run{Children: []interface{}{&CommentReference{ID: c.ID}}})
+			break
+		}
+	}
+	return c, nil
+}
+
+// Reply adds a comment of text by author replying c,
+// which shares the same anchored text with c.
+func (c *Comment) Reply(author, text string) *Comment {
+	nc := c.file.addComment(author, text)
+	nc.Parent = c
+	walkParagraphs(c.file.Document.Body.Items, func(p *Paragraph) bool {
+		for i := 0; i < len(p.Children); i++ {
+			switch o := p.Children[i].(type) {
+			case *CommentRangeStart:
+				if o.ID == c.ID {
+					i++
+					p.insertChild(i, &CommentRangeStart{ID: nc.ID})
+				}
+			case *CommentRangeEnd:
+				if o.ID == c.ID {
+					i++
+					p.insertChild(i, &CommentRangeEnd{ID: nc.ID})
+				}
+			case *Run:
+				for _, x := range o.Children {
+					if ref, ok := x.(*CommentReference); ok && ref.ID == c.ID {
+						i++
+						p.insertChild(i, &Run{Children: []interface{}{&CommentReference{ID: nc.ID}}})
+						break
+					}
+				}
+			}
+		}
+		return true
+	})
+	return nc
+}
+
+// AddParagraph adds a new paragraph
+func (c *Comment) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     c.file,
+	}
+	c.Items = append(c.Items, p)
+	return p
+}
+
+// String returns the plain text of the comment
+func (c *Comment) String() string {
+	sb := strings.Builder{}
+	for _, it := range c.Items {
+		if p, ok := it.(*Paragraph); ok {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(p.String())
+		}
+	}
+	return sb.String()
+}
+
+// AnchoredText returns the plain text of the document
+// between the range start and end of the comment
+func (c *Comment) AnchoredText() string {
+	sb := strings.Builder{}
+	in := false
+	walkParagraphs(c.file.Document.Body.Items, func(p *Paragraph) bool {
+		for _, x := range p.Children {
+			switch o := x.(type) {
+			case *CommentRangeStart:
+				if o.ID == c.ID {
+					in = true
+				}
+			case *CommentRangeEnd:
+				if o.ID == c.ID {
+					in = false
+					return false
+				}
+			case *Run:
+				if in {
+					sb.WriteString(runText(o))
+				}
+			case *Hyperlink:
+				if in {
+					sb.WriteString(runText(&o.Run))
+				}
+			}
+		}
+		if in {
+			sb.WriteByte('\n')
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// runText returns the plain text in r
+func runText(r *Run) string {
+	sb := strings.Builder{}
+	for _, c := range r.Children {
+		switch x := c.(type) {
+		case *Text:
+			sb.WriteString(x.Text)
+		case *Tab:
+			sb.WriteByte('\t')
+		case *BarterRabbet:
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// lastParagraph returns the last paragraph of c, or nil if there's none
+func (c *Comment) lastParagraph() *Paragraph {
+	for i := len(c.Items) - 1; i >= 0; i-- {
+		if p, ok := c.Items[i].(*Paragraph); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// applyEx sets Parent and Done of the comments by commentsExtended
+func (cs *Comments) applyEx(ex *CommentsEx) {
+	bypara := make(map[string]*Comment, len(cs.Comments))
+	for _, c := range cs.Comments {
+		if p := c.lastParagraph(); p != nil && p.ParaID != "" {
+			bypara[p.ParaID] = c
+		}
+	}
+	for _, x := range ex.CommentExs {
+		c, ok := bypara[x.ParaID]
+		if !ok {
+			continue
+		}
+		c.Done = x.Done == "1" || x.Done == "true"
+		if x.ParaIDParent != "" {
+			c.Parent = bypara[x.ParaIDParent]
+		}
+	}
+}
+
+// needEx reports whether commentsExtended should be written
+func (cs *Comments) needEx() bool {
+	if cs.exname != "" {
+		return true
+	}
+	for _, c := range cs.Comments {
+		if c.Parent != nil || c.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// makeEx gives a w14:paraId to the last paragraph of each comment
+// if not have and returns the commentsExtended of the comments
+func (cs *Comments) makeEx() *CommentsEx {
+	used := make(map[string]struct{}, 64)
+	walkParagraphs(cs.file.Document.Body.Items, func(p *Paragraph) bool {
+		if p.ParaID != "" {
+			used[p.ParaID] = struct{}{}
+		}
+		return true
+	})
+	for _, c := range cs.Comments {
+		if p := c.lastParagraph(); p != nil && p.ParaID != "" {
+			used[p.ParaID] = struct{}{}
+		}
+	}
+	n := uint32(0)
+	ex := &CommentsEx{XMLW15: XMLNS_W15, CommentExs: make([]*CommentEx, 0, len(cs.Comments))}
+	for _, c := range cs.Comments {
+		p := c.lastParagraph()
+		if p == nil {
+			continue
+		}
+		for p.ParaID == "" {
+			n++ // paraId must be less than 0x80000000
+			id := fmt.Sprintf("%08X", n)
+			if _, ok := used[id]; !ok {
+				used[id] = struct{}{}
+				p.ParaID = id
+			}
+		}
+	}
+	for _, c := range cs.Comments {
+		p := c.lastParagraph()
+		if p == nil {
+			continue
+		}
+		x := &CommentEx{ParaID: p.ParaID, Done: "0"}
+		if c.Done {
+			x.Done = "1"
+		}
+		if c.Parent != nil {
+			if pp := c.Parent.lastParagraph(); pp != nil {
+				x.ParaIDParent = pp.ParaID
+			}
+		}
+		ex.CommentExs = append(ex.CommentExs, x)
+	}
+	return ex
+}
+
+// copycomment copies the comment of id from f to the file to,
+// only once for each comment, and returns the id in to
+func (f *Docx) copycomment(id int, to *Docx) (int, bool) {
+	src := f.comments.Comment(id)
+	if src == nil {
+		return 0, false
+	}
+	if dst, ok := to.copiedComments[src]; ok {
+		return dst.ID, true
+	}
+	dst := to.addComment(src.Author, "")
+	dst.Date = src.Date
+	dst.Initials = src.Initials
+	dst.Done = src.Done
+	if src.Parent != nil {
+		dst.Parent = to.copiedComments[src.Parent]
+	}
+	dst.Items = dst.Items[:0]
+	for _, it := range src.Items {
+		switch o := it.(type) {
+		case *Paragraph:
+			np := o.copymedia(to)
+			np.ParaID = ""
+			dst.Items = append(dst.Items, &np)
+		case *Table:
+			nt := o.copymedia(to)
+			dst.Items = append(dst.Items, &nt)
+		default:
+			dst.Items = append(dst.Items, it)
+		}
+	}
+	if to.copiedComments == nil {
+		to.copiedComments = make(map[*Comment]*Comment, 16)
+	}
+	to.copiedComments[src] = dst
+	return dst.ID, true
+}
+
+// copycomments copies the comments referred in r from f to the file to,
+// and points the references to the new comments
+func (f *Docx) copycomments(r *Run, to *Docx) {
+	if f == nil || f == to {
+		return
+	}
+	for i, c := range r.Children {
+		if ref, ok := c.(*CommentReference); ok {
+			if id, ok := f.copycomment(ref.ID, to); ok {
+				r.Children[i] = &CommentReference{ID: id}
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+// ContentTypes loads [Content_Types].xml of the template (or makes the minimum one)
+// if it is not parsed. The overrides of the missing parts and the defaults
+// are regenerated from the files in the package on packing.
+func (f *Docx) ContentTypes() (*ContentTypes, error) {
+	if f.contentTypes != nil {
+		return f.contentTypes, nil
+	}
+	for _, name := range f.tmpfslst {
+		if name != CONTENT_TYPES_NAME {
+			continue
+		}
+		file, err := f.openTemplateFile(name)
+		if err != nil {
+			return nil, err
+		}
+		f.contentTypes, err = readContentTypes(file)
+		return f.contentTypes, err
+	}
+	f.contentTypes = newContentTypes()
+	return f.contentTypes, nil
+}
@@ -0,0 +1,217 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedPropertyType the value cannot be stored in a custom property
+var ErrUnsupportedPropertyType = errors.New("unsupported custom property type")
+
+// CoreProperties loads docProps/core.xml (or makes an empty one) and keeps it
+// for packing, so that the changes on it will be saved.
+func (f *Docx) CoreProperties() (*CoreProperties, error) {
+	if f.coreProps != nil {
+		return f.coreProps, nil
+	}
+	cp := &CoreProperties{
+		XMLCP:       XMLNS_CP,
+		XMLDC:       XMLNS_DC,
+		XMLDCTerms:  XMLNS_DCTERMS,
+		XMLDCMIType: XMLNS_DCMITYPE,
+		XMLXSI:      XMLNS_XSI,
+		name:        "docProps/core.xml",
+	}
+	err := f.loadPackagePart(REL_CORE_PROPERTIES, &cp.name, cp)
+	if err != nil {
+		return nil, err
+	}
+	f.coreProps = cp
+	return cp, nil
+}
+
+// AppProperties loads docProps/app.xml (or makes an empty one) and keeps it
+// for packing, so that the changes on it will be saved.
+func (f *Docx) AppProperties() (*AppProperties, error) {
+	if f.appProps != nil {
+		return f.appProps, nil
+	}
+	ap := &AppProperties{
+		Xmlns: XMLNS_EXTENDED_PROPERTIES,
+		XMLVT: XMLNS_VT,
+		name:  "docProps/app.xml",
+	}
+	err := f.loadPackagePart(REL_EXTENDED_PROPERTIES, &ap.name, ap)
+	if err != nil {
+		return nil, err
+	}
+	f.appProps = ap
+	return ap, nil
+}
+
+// CustomProperties loads docProps/custom.xml (or makes an empty one) and keeps it
+// for packing, so that the changes on it will be saved.
+func (f *Docx) CustomProperties() (*CustomProperties, error) {
+	if f.customProps != nil {
+		return f.customProps, nil
+	}
+	cps := &CustomProperties{
+		Xmlns: XMLNS_CUSTOM_PROPERTIES,
+		XMLVT: XMLNS_VT,
+		name:  "docProps/custom.xml",
+	}
+	err := f.loadPackagePart(REL_CUSTOM_PROPERTIES, &cps.name, cps)
+	if err != nil {
+		return nil, err
+	}
+	f.customProps = cps
+	return cps, nil
+}
+
+// loadPackagePart decodes the part of typ in _rels/.rels into v
+// and sets name to its path if found
+func (f *Docx) loadPackagePart(typ string, name *string, v interface{}) error {
+	has := func(n string) bool {
+		for _, x := range f.tmpfslst {
+			if x == n {
+				return true
+			}
+		}
+		return false
+	}
+	if has("_rels/.rels") {
+		file, err := f.openTemplateFile("_rels/.rels")
+		if err != nil {
+			return err
+		}
+		rels, err := readRelationships(file)
+		if err != nil {
+			return err
+		}
+		for _, r := range rels.Relationship {
+			if r.Type == typ {
+				*name = strings.TrimPrefix(r.Target, "/")
+				break
+			}
+		}
+	}
+	if !has(*name) {
+		return nil
+	}
+	file, err := f.openTemplateFile(*name)
+	if err != nil {
+		return err
+	}
+	err = newPartDecoder(file).Decode(v)
+	_ = file.Close()
+	return err
+}
+
+// Property gets the custom property (or nil on notfound) by name
+func (cps *CustomProperties) Property(name string) *CustomProperty {
+	for _, p := range cps.Properties {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// SetProperty adds or replaces the custom property of name.
+//
+// value can be string, bool, int, int32, int64, float32, float64 or time.Time
+func (cps *CustomProperties) SetProperty(name string, value interface{}) (*CustomProperty, error) {
+	var typ, val string
+	switch v := value.(type) {
+	case string:
+		typ, val = VT_LPWSTR, v
+	case bool:
+		typ, val = VT_BOOL, strconv.FormatBool(v)
+	case int:
+		typ, val = intProperty(int64(v))
+	case int32:
+		typ, val = intProperty(int64(v))
+	case int64:
+		typ, val = intProperty(v)
+	case float32:
+		typ, val = VT_R8, strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		typ, val = VT_R8, strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		typ, val = VT_FILETIME, v.UTC().Format(time.RFC3339)
+	default:
+		return nil, ErrUnsupportedPropertyType
+	}
+	p := cps.Property(name)
+	if p == nil {
+		pid := 1 // pid 0 and 1 are reserved
+		for _, x := range cps.Properties {
+			if x.PID > pid {
+				pid = x.PID
+			}
+		}
+		p = &CustomProperty{FmtID: CUSTOM_PROPERTY_FMTID, PID: pid + 1, Name: name}
+		cps.Properties = append(cps.Properties, p)
+	}
+	p.Type, p.Value = typ, val
+	return p, nil
+}
+
+// intProperty uses i8 only if v overflows i4
+func intProperty(v int64) (string, string) {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return "i8", strconv.FormatInt(v, 10)
+	}
+	return VT_I4, strconv.FormatInt(v, 10)
+}
+
+// RemoveProperty removes the custom property of name and reports whether it existed
+func (cps *CustomProperties) RemoveProperty(name string) bool {
+	for i, p := range cps.Properties {
+		if p.Name == name {
+			cps.Properties = append(cps.Properties[:i], cps.Properties[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Val parses Value by Type into string, bool, int64, float64 or time.Time.
+// Unknown types are returned as the string Value.
+func (p *CustomProperty) Val() (interface{}, error) {
+	switch p.Type {
+	case VT_BOOL:
+		return strconv.ParseBool(strings.TrimSpace(p.Value))
+	case "i1", "i2", VT_I4, "i8", "int", "ui1", "ui2", "ui4", "ui8", "uint":
+		return strconv.ParseInt(strings.TrimSpace(p.Value), 10, 64)
+	case "r4", VT_R8, "decimal":
+		return strconv.ParseFloat(strings.TrimSpace(p.Value), 64)
+	case VT_FILETIME, "date":
+		return time.Parse(time.RFC3339, strings.TrimSpace(p.Value))
+	default:
+		return p.Value, nil
+	}
+}
@@ -100,6 +100,8 @@ func (p *Paragraph) AddInlineDrawing(pic []byte) (*Run, error) {
 			},
 		},
 	}
+	d.file = p.file
+	d.Inline.file = p.file
 	c := make([]interface{}, 1, 64)
 	c[0] = d
 	run := &Run{
@@ -211,6 +213,8 @@ func (p *Paragraph) AddAnchorDrawing(pic []byte) (*Run, error) {
 			},
 		},
 	}
+	d.file = p.file
+	d.Anchor.file = p.file
 	c := make([]interface{}, 1, 64)
 	c[0] = d
 	run := &Run{
@@ -241,3 +245,26 @@ func (r *WPAnchor) Size(w, h int64) {
 		r.Graphic.GraphicData.Pic.SpPr.Xfrm.Ext.CY = h
 	}
 }
+
+// picture returns the rId of the picture in d with its alt text (or name)
+// and size, or an empty embed if d is not a picture
+func (d *Drawing) picture() (embed, alt string, ext *WPExtent) {
+	var g *AGraphic
+	var docpr *WPDocPr
+	switch {
+	case d.Inline != nil:
+		g, docpr, ext = d.Inline.Graphic, d.Inline.DocPr, d.Inline.Extent
+	case d.Anchor != nil:
+		g, docpr, ext = d.Anchor.Graphic, d.Anchor.DocPr, d.Anchor.Extent
+	}
+	if g == nil || g.GraphicData == nil || g.GraphicData.Pic == nil || g.GraphicData.Pic.BlipFill == nil {
+		return "", "", nil
+	}
+	if docpr != nil {
+		alt = docpr.Descr
+		if alt == "" {
+			alt = docpr.Name
+		}
+	}
+	return g.GraphicData.Pic.BlipFill.Blip.Embed, alt, ext
+}
@@ -0,0 +1,379 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strings"
+	"time"
+)
+
+// Field is a w:fldSimple or the runs of a complex field
+// from w:fldChar begin to end
+type Field struct {
+	Simple *SimpleField // Simple is the w:fldSimple, or nil for a complex field
+
+	Begin    *Run   // Begin is the run of w:fldChar begin
+	Instr    []*Run // Instr are the runs of w:instrText
+	Separate *Run   // Separate is the run of w:fldChar separate, or nil if no result
+	Result   []*Run // Result are the runs of the cached result, including nested fields
+	End      *Run   // End is the run of w:fldChar end, or nil if the field is broken
+
+	// parents are the children of the paragraph (or w:ins, w:fldSimple ...)
+	// where the runs are, or nil for the run of a w:hyperlink, in which all
+	// the runs are merged, so that Begin, Instr, Separate and End are the same
+	parents map[*Run]*[]interface{}
+}
+
+// Fields returns all the fields in the document body, headers and footers
+func (f *Docx) Fields() []*Field {
+	fields := make([]*Field, 0, 16)
+	collect := func(items []interface{}) {
+		var stack []*Field
+		walkParagraphs(items, func(p *Paragraph) bool {
+			fields, stack = appendFields(fields, stack, &p.Children)
+			return true
+		})
+	}
+	collect(f.Document.Body.Items)
+	for _, h := range f.headers {
+		collect(h.Items)
+	}
+	for _, ft := range f.footers {
+		collect(ft.Items)
+	}
+	return fields
+}
+
+// appendFields appends the fields started in children to fields, where stack
+// are the complex fields not ended yet
+func appendFields(fields, stack []*Field, children *[]interface{}) ([]*Field, []*Field) {
+	for _, c := range *children {
+		switch o := c.(type) {
+		case *SimpleField:
+			fields = append(fields, &Field{Simple: o})
+			fields, stack = appendFields(fields, stack, &o.Children)
+		case *Revision:
+			fields, stack = appendFields(fields, stack, &o.Children)
+		case *Hyperlink:
+			fields, stack = appendRunFields(fields, stack, &o.Run, nil)
+		case *Run:
+			fields, stack = appendRunFields(fields, stack, o, children)
+		}
+	}
+	return fields, stack
+}
+
+// appendRunFields appends the fields started in the run o to fields,
+// where parent are the children containing o
+func appendRunFields(fields, stack []*Field, o *Run, parent *[]interface{}) ([]*Field, []*Field) {
+	isfldchar := false
+	for _, x := range o.Children {
+		fc, ok := x.(*FieldChar)
+		if !ok {
+			continue
+		}
+		isfldchar = true
+		switch fc.Type {
+		case FIELD_CHAR_BEGIN:
+			fd := &Field{Begin: o, parents: make(map[*Run]*[]interface{}, 8)}
+			fd.parents[o] = parent
+			if o.InstrText != "" {
+				fd.Instr = []*Run{o} // merged runs
+			}
+			fields = append(fields, fd)
+			stack = append(stack, fd)
+		case FIELD_CHAR_SEPARATE:
+			if len(stack) > 0 {
+				fd := stack[len(stack)-1]
+				fd.Separate = o
+				fd.parents[o] = parent
+			}
+		case FIELD_CHAR_END:
+			if len(stack) > 0 {
+				fd := stack[len(stack)-1]
+				fd.End = o
+				fd.parents[o] = parent
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if len(stack) == 0 {
+		return fields, stack
+	}
+	if fd := stack[len(stack)-1]; !isfldchar && fd.Separate == nil && o.InstrText != "" {
+		fd.Instr = append(fd.Instr, o)
+		fd.parents[o] = parent
+	}
+	// nested fields are parts of the result of the outer ones
+	for _, fd := range stack {
+		if fd.Separate != nil && fd.Separate != o {
+			fd.Result = append(fd.Result, o)
+			fd.parents[o] = parent
+		}
+	}
+	return fields, stack
+}
+
+// Instruction returns the field code like PAGE or REF bookmark \h
+func (fd *Field) Instruction() string {
+	if fd.Simple != nil {
+		return strings.TrimSpace(fd.Simple.Instr)
+	}
+	sb := strings.Builder{}
+	for _, r := range fd.Instr {
+		sb.WriteString(r.InstrText)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// Type returns the upper case name of the field like PAGE, DATE or REF
+func (fd *Field) Type() string {
+	s := fd.Instruction()
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ToUpper(s)
+}
+
+// ResultText returns the plain text of the cached result
+func (fd *Field) ResultText() string {
+	if fd.Simple != nil {
+		return (&Paragraph{Children: fd.Simple.Children}).String()
+	}
+	if fd.merged() {
+		if sep, end := fd.mergedResult(); sep < end {
+			return runText(&Run{Children: fd.End.Children[sep+1 : end]})
+		}
+		return ""
+	}
+	sb := strings.Builder{}
+	for _, r := range fd.Result {
+		sb.WriteString(runText(r))
+	}
+	return sb.String()
+}
+
+// merged reports whether all the runs of the field are merged
+// into one, which is the run of a w:hyperlink
+func (fd *Field) merged() bool {
+	return fd.End != nil && fd.End == fd.Begin
+}
+
+// mergedResult returns the indices of w:fldChar separate (or end if
+// there is no result) and w:fldChar end in the children of the merged run
+func (fd *Field) mergedResult() (sep, end int) {
+	sep, end = -1, -1
+	for i, c := range fd.End.Children {
+		if fc, ok := c.(*FieldChar); ok {
+			switch fc.Type {
+			case FIELD_CHAR_SEPARATE:
+				sep = i
+			case FIELD_CHAR_END:
+				end = i
+			}
+		}
+	}
+	if sep < 0 || sep > end {
+		sep = end
+	}
+	return
+}
+
+// remove removes the run r of the field from its parent
+func (fd *Field) remove(r *Run) {
+	if parent := fd.parents[r]; parent != nil {
+		*parent = removeItem(*parent, r)
+	} else {
+		r.Children = nil // the run of a w:hyperlink
+	}
+	delete(fd.parents, r)
+}
+
+// SetInstruction replaces the field code by instr
+func (fd *Field) SetInstruction(instr string) {
+	if fd.Simple != nil {
+		fd.Simple.Instr = instr
+		return
+	}
+	if len(fd.Instr) == 0 {
+		parent := fd.parents[fd.Begin]
+		if parent == nil {
+			fd.Begin.InstrText = instr // the run of a w:hyperlink
+			fd.Instr = []*Run{fd.Begin}
+			return
+		}
+		r := &Run{InstrText: instr, file: fd.Begin.file}
+		*parent = insertItem(*parent, indexOf(*parent, fd.Begin)+1, r)
+		fd.Instr = []*Run{r}
+		fd.parents[r] = parent
+		return
+	}
+	fd.Instr[0].InstrText = instr
+	for _, r := range fd.Instr[1:] {
+		fd.remove(r)
+	}
+	fd.Instr = fd.Instr[:1]
+}
+
+// SetResult replaces the cached result by text, in the format
+// of the first run of the former result
+func (fd *Field) SetResult(text string) {
+	if fd.Simple != nil {
+		r := &Run{Children: []interface{}{&Text{Text: text, XMLSpace: "preserve"}}, file: fd.Simple.file}
+		for _, c := range fd.Simple.Children {
+			if x, ok := c.(*Run); ok {
+				r.RunProperties = x.RunProperties
+				break
+			}
+		}
+		fd.Simple.Children = []interface{}{r}
+		return
+	}
+	if fd.End == nil {
+		return // broken field
+	}
+	if fd.merged() {
+		sep, end := fd.mergedResult()
+		children := append(make([]interface{}, 0, len(fd.End.Children)+2), fd.End.Children[:sep]...)
+		children = append(children, &FieldChar{Type: FIELD_CHAR_SEPARATE}, &Text{Text: text, XMLSpace: "preserve"})
+		if sep < end {
+			children[sep] = fd.End.Children[sep]
+		}
+		fd.End.Children = append(children, fd.End.Children[end:]...)
+		fd.Separate = fd.End
+		return
+	}
+	if len(fd.Result) == 0 {
+		parent := fd.parents[fd.End]
+		i := indexOf(*parent, fd.End)
+		if fd.Separate == nil {
+			fd.Separate = &Run{Children: []interface{}{&FieldChar{Type: FIELD_CHAR_SEPARATE}}, file: fd.End.file}
+			*parent = insertItem(*parent, i, fd.Separate)
+			fd.parents[fd.Separate] = parent
+			i++
+		}
+		r := &Run{Children: []interface{}{&Text{Text: text, XMLSpace: "preserve"}}, file: fd.End.file}
+		*parent = insertItem(*parent, i, r)
+		fd.Result = []*Run{r}
+		fd.parents[r] = parent
+		return
+	}
+	fd.Result[0].Children = []interface{}{&Text{Text: text, XMLSpace: "preserve"}}
+	for _, r := range fd.Result[1:] {
+		fd.remove(r)
+	}
+	fd.Result = fd.Result[:1]
+}
+
+// MarkDirty asks the reader like Word to update the field on opening
+func (fd *Field) MarkDirty() {
+	if fd.Simple != nil {
+		fd.Simple.Dirty = true
+		return
+	}
+	for _, c := range fd.Begin.Children {
+		if fc, ok := c.(*FieldChar); ok {
+			fc.Dirty = true
+			return
+		}
+	}
+}
+
+// indexOf returns the index of x in items, or -1 if not found
+func indexOf(items []interface{}, x interface{}) int {
+	for i, c := range items {
+		if c == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertItem inserts x into items at i
+func insertItem(items []interface{}, i int, x interface{}) []interface{} {
+	items = append(items, nil)
+	copy(items[i+1:], items[i:])
+	items[i] = x
+	return items
+}
+
+// removeItem removes x from items
+func removeItem(items []interface{}, x interface{}) []interface{} {
+	if i := indexOf(items, x); i >= 0 {
+		items = append(items[:i], items[i+1:]...)
+	}
+	return items
+}
+
+// AddField adds a complex field of instr with its cached result at the end of p
+func (p *Paragraph) AddField(instr, result string) *Field {
+	fd := &Field{
+		Begin:    &Run{Children: []interface{}{&FieldChar{Type: FIELD_CHAR_BEGIN}}, file: p.file},
+		Instr:    []*Run{{InstrText: instr, file: p.file}},
+		Separate: &Run{Children: []interface{}{&FieldChar{Type: FIELD_CHAR_SEPARATE}}, file: p.file},
+		End:      &Run{Children: []interface{}{&FieldChar{Type: FIELD_CHAR_END}}, file: p.file},
+		parents:  make(map[*Run]*[]interface{}, 8),
+	}
+	p.Children = append(p.Children, fd.Begin, fd.Instr[0], fd.Separate)
+	fd.Result = []*Run{p.AddText(result)}
+	p.Children = append(p.Children, fd.End)
+	for _, r := range []*Run{fd.Begin, fd.Instr[0], fd.Separate, fd.Result[0], fd.End} {
+		fd.parents[r] = &p.Children
+	}
+	return fd
+}
+
+// AddPageField adds the current page number
+func (p *Paragraph) AddPageField() *Field {
+	return p.AddField("PAGE", "1")
+}
+
+// AddNumPagesField adds the total number of pages
+func (p *Paragraph) AddNumPagesField() *Field {
+	return p.AddField("NUMPAGES", "1")
+}
+
+// AddDateField adds the current date in Word date format like yyyy-MM-dd,
+// or in the default format of Word if format is empty
+func (p *Paragraph) AddDateField(format string) *Field {
+	if format == "" {
+		return p.AddField("DATE", time.Now().Format("1/2/2006"))
+	}
+	return p.AddField(`DATE \@ "`+format+`"`, time.Now().Format(wordDateLayout.Replace(format)))
+}
+
+// AddRefField adds a reference to the text of bookmark, with text as the
+// cached result and a link to the bookmark. It will be updated on opening.
+func (p *Paragraph) AddRefField(bookmark, text string) *Field {
+	fd := p.AddField("REF "+bookmark+` \h`, text)
+	fd.MarkDirty()
+	return fd
+}
+
+// wordDateLayout converts Word date format into go time layout
+var wordDateLayout = strings.NewReplacer(
+	"yyyy", "2006", "yy", "06",
+	"MMMM", "January", "MMM", "Jan", "MM", "01", "M", "1",
+	"dddd", "Monday", "ddd", "Mon", "dd", "02", "d", "2",
+	"HH", "15", "H", "15", "hh", "03", "h", "3",
+	"mm", "04", "m", "4", "ss", "05", "s", "5",
+	"AM/PM", "PM", "am/pm", "pm",
+)
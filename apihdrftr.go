@@ -0,0 +1,306 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import "strconv"
+
+func (f *Docx) newHeader(id, name string) *Header {
+	return &Header{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		XMLW14: XMLNS_W14,
+		Items:  make([]interface{}, 0, 8),
+		id:     id,
+		name:   name,
+		file:   f,
+	}
+}
+
+func (f *Docx) newFooter(id, name string) *Footer {
+	return &Footer{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		XMLW14: XMLNS_W14,
+		Items:  make([]interface{}, 0, 8),
+		id:     id,
+		name:   name,
+		file:   f,
+	}
+}
+
+// newPartName finds an unused file name under word/ like header1.xml
+func (f *Docx) newPartName(prefix string) string {
+	used := make(map[string]struct{}, len(f.headers)+len(f.footers)+len(f.tmpfslst))
+	for _, h := range f.headers {
+		used[h.name] = struct{}{}
+	}
+	for _, ft := range f.footers {
+		used[ft.name] = struct{}{}
+	}
+	for _, name := range f.tmpfslst {
+		if len(name) > 5 && name[:5] == "word/" {
+			used[name[5:]] = struct{}{}
+		}
+	}
+	for i := 1; ; i++ {
+		name := prefix + strconv.Itoa(i) + ".xml"
+		if _, ok := used[name]; !ok {
+			return name
+		}
+	}
+}
+
+// AddHeader adds a new empty header part into the file.
+// Use SectPr.WithHeader to show it in a section.
+func (f *Docx) AddHeader() *Header {
+	name := f.newPartName("header")
+	h := f.newHeader(f.addPartRelation(REL_HEADER, name), name)
+	f.headers = append(f.headers, h)
+	return h
+}
+
+// AddFooter adds a new empty footer part into the file.
+// Use SectPr.WithFooter to show it in a section.
+func (f *Docx) AddFooter() *Footer {
+	name := f.newPartName("footer")
+	ft := f.newFooter(f.addPartRelation(REL_FOOTER, name), name)
+	f.footers = append(f.footers, ft)
+	return ft
+}
+
+// Headers returns all header parts in the file
+func (f *Docx) Headers() []*Header {
+	return f.headers
+}
+
+// Footers returns all footer parts in the file
+func (f *Docx) Footers() []*Footer {
+	return f.footers
+}
+
+// Header gets the header (or nil on notfound) by rId
+func (f *Docx) Header(id string) *Header {
+	for _, h := range f.headers {
+		if h.id == id {
+			return h
+		}
+	}
+	return nil
+}
+
+// Footer gets the footer (or nil on notfound) by rId
+func (f *Docx) Footer(id string) *Footer {
+	for _, ft := range f.footers {
+		if ft.id == id {
+			return ft
+		}
+	}
+	return nil
+}
+
+// ID is the rId of the header in document relationships
+func (h *Header) ID() string {
+	return h.id
+}
+
+// AddParagraph adds a new paragraph
+func (h *Header) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     h.file,
+	}
+	h.Items = append(h.Items, p)
+	return p
+}
+
+// AddTable add a new table to header by col*row
+func (h *Header) AddTable(row int, col int) *Table {
+	tbl := h.file.newTable(row, col)
+	h.Items = append(h.Items, tbl)
+	return tbl
+}
+
+// AddTableTwips add a new table to header by height and width
+//
+// unit: twips (1/20 point)
+func (h *Header) AddTableTwips(rowHeights []int64, colWidths []int64) *Table {
+	tbl := h.file.newTableTwips(rowHeights, colWidths)
+	h.Items = append(h.Items, tbl)
+	return tbl
+}
+
+// ID is the rId of the footer in document relationships
+func (ft *Footer) ID() string {
+	return ft.id
+}
+
+// AddParagraph adds a new paragraph
+func (ft *Footer) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     ft.file,
+	}
+	ft.Items = append(ft.Items, p)
+	return p
+}
+
+// AddTable add a new table to footer by col*row
+func (ft *Footer) AddTable(row int, col int) *Table {
+	tbl := ft.file.newTable(row, col)
+	ft.Items = append(ft.Items, tbl)
+	return tbl
+}
+
+// AddTableTwips add a new table to footer by height and width
+//
+// unit: twips (1/20 point)
+func (ft *Footer) AddTableTwips(rowHeights []int64, colWidths []int64) *Table {
+	tbl := ft.file.newTableTwips(rowHeights, colWidths)
+	ft.Items = append(ft.Items, tbl)
+	return tbl
+}
+
+// WithHeader shows h in the section on the pages of typ,
+// replacing the former one of the same typ.
+// The first page one also enables TitlePg of the section.
+//
+//	typ 的取值可以是以下之一：
+//		default：默认页眉。
+//		first：首页页眉。
+//		even：偶数页页眉。
+func (sect *SectPr) WithHeader(typ string, h *Header) *SectPr {
+	if typ == "first" && sect.TitlePg == nil {
+		sect.TitlePg = &TitlePg{}
+	}
+	for _, ref := range sect.HeaderReferences {
+		if ref.Type == typ {
+			ref.ID = h.id
+			return sect
+		}
+	}
+	sect.HeaderReferences = append(sect.HeaderReferences, &HeaderReference{Type: typ, ID: h.id})
+	return sect
+}
+
+// WithFooter shows ft in the section on the pages of typ,
+// replacing the former one of the same typ.
+// The first page one also enables TitlePg of the section.
+//
+//	typ 的取值可以是以下之一：
+//		default：默认页脚。
+//		first：首页页脚。
+//		even：偶数页页脚。
+func (sect *SectPr) WithFooter(typ string, ft *Footer) *SectPr {
+	if typ == "first" && sect.TitlePg == nil {
+		sect.TitlePg = &TitlePg{}
+	}
+	for _, ref := range sect.FooterReferences {
+		if ref.Type == typ {
+			ref.ID = ft.id
+			return sect
+		}
+	}
+	sect.FooterReferences = append(sect.FooterReferences, &FooterReference{Type: typ, ID: ft.id})
+	return sect
+}
+
+// copysect copies the section properties from f to the file to,
+// with the headers and footers referred copied only once for each part.
+// The references are dropped if f is nil.
+func (f *Docx) copysect(sect *SectPr, to *Docx) *SectPr {
+	n := sect.clone()
+	if f == to {
+		return n
+	}
+	others := n.Others[:0]
+	for _, o := range n.Others {
+		// the printer settings are a part related in f only
+		if o.Name().Local != "printerSettings" {
+			others = append(others, o)
+		}
+	}
+	n.Others = others
+	if f == nil {
+		n.HeaderReferences, n.FooterReferences = nil, nil
+		return n
+	}
+	hrefs := n.HeaderReferences[:0]
+	for _, ref := range n.HeaderReferences {
+		if h := f.Header(ref.ID); h != nil {
+			ref.ID = f.copypart(h, to)
+			hrefs = append(hrefs, ref)
+		}
+	}
+	n.HeaderReferences = hrefs
+	frefs := n.FooterReferences[:0]
+	for _, ref := range n.FooterReferences {
+		if ft := f.Footer(ref.ID); ft != nil {
+			ref.ID = f.copypart(ft, to)
+			frefs = append(frefs, ref)
+		}
+	}
+	n.FooterReferences = frefs
+	return n
+}
+
+// copypart copies the header or footer part from f to the file to
+// and returns its rId in to
+func (f *Docx) copypart(part interface{}, to *Docx) string {
+	if id, ok := to.copiedParts[part]; ok {
+		return id
+	}
+	var src []interface{}
+	var dst *[]interface{}
+	var id string
+	switch o := part.(type) {
+	case *Header:
+		h := to.AddHeader()
+		src, dst, id = o.Items, &h.Items, h.id
+	case *Footer:
+		ft := to.AddFooter()
+		src, dst, id = o.Items, &ft.Items, ft.id
+	}
+	for _, it := range src {
+		switch o := it.(type) {
+		case *Paragraph:
+			np := o.copymedia(to)
+			*dst = append(*dst, &np)
+		case *Table:
+			nt := o.copymedia(to)
+			*dst = append(*dst, &nt)
+		default:
+			*dst = append(*dst, to.copybookmark(f, o))
+		}
+	}
+	if to.copiedParts == nil {
+		to.copiedParts = make(map[interface{}]string, 4)
+	}
+	to.copiedParts[part] = id
+	return id
+}
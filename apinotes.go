@@ -0,0 +1,256 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// newSeparatorNotes makes the separator entries required by Word
+func newSeparatorNotes(local string, file *Docx) []*Note {
+	return []*Note{
+		{
+			XMLName: xml.Name{Local: "w:" + local},
+			Type:    "separator",
+			ID:      -1,
+			Items: []interface{}{&Paragraph{
+				Children: []interface{}{&Run{Children: []interface{}{&Separator{}}}},
+				file:     file,
+			}},
+			file: file,
+		},
+		{
+			XMLName: xml.Name{Local: "w:" + local},
+			Type:    "continuationSeparator",
+			ID:      0,
+			Items: []interface{}{&Paragraph{
+				Children: []interface{}{&Run{Children: []interface{}{&ContinuationSeparator{}}}},
+				file:     file,
+			}},
+			file: file,
+		},
+	}
+}
+
+func (f *Docx) newFootnotes(name string) *Footnotes {
+	return &Footnotes{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		XMLW14: XMLNS_W14,
+		Notes:  newSeparatorNotes("footnote", f),
+		name:   name,
+		file:   f,
+	}
+}
+
+func (f *Docx) newEndnotes(name string) *Endnotes {
+	return &Endnotes{
+		XMLW:   XMLNS_W,
+		XMLR:   XMLNS_R,
+		XMLWP:  XMLNS_WP,
+		XMLWPS: XMLNS_WPS,
+		XMLWPC: XMLNS_WPC,
+		XMLWPG: XMLNS_WPG,
+		XMLW14: XMLNS_W14,
+		Notes:  newSeparatorNotes("endnote", f),
+		name:   name,
+		file:   f,
+	}
+}
+
+// Footnotes returns word/footnotes.xml, or nil if there's no footnote
+func (f *Docx) Footnotes() *Footnotes {
+	return f.footnotes
+}
+
+// Endnotes returns word/endnotes.xml, or nil if there's no endnote
+func (f *Docx) Endnotes() *Endnotes {
+	return f.endnotes
+}
+
+// Note gets the footnote (or nil on notfound) by id
+func (fn *Footnotes) Note(id int) *Note {
+	if fn == nil {
+		return nil
+	}
+	return findNote(fn.Notes, id)
+}
+
+// Note gets the endnote (or nil on notfound) by id
+func (en *Endnotes) Note(id int) *Note {
+	if en == nil {
+		return nil
+	}
+	return findNote(en.Notes, id)
+}
+
+func findNote(notes []*Note, id int) *Note {
+	for _, n := range notes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// newNote appends a new note with the next ID, whose first paragraph
+// starts with mark
+func newNote(notes []*Note, local string, mark interface{}, file *Docx) ([]*Note, *Note) {
+	n := &Note{
+		XMLName: xml.Name{Local: "w:" + local},
+		ID:      1,
+		Items:   make([]interface{}, 0, 4),
+		file:    file,
+	}
+	for _, x := range notes {
+		if x.ID >= n.ID {
+			n.ID = x.ID + 1
+		}
+	}
+	n.AddParagraph().Children = append(make([]interface{}, 0, 64), &Run{
+		RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+		Children:      []interface{}{mark},
+	})
+	return append(notes, n), n
+}
+
+// addFootnote makes word/footnotes.xml if not exist and adds an empty note
+func (f *Docx) addFootnote() *Note {
+	if f.footnotes == nil {
+		f.footnotes = f.newFootnotes("footnotes.xml")
+		f.addPartRelation(REL_FOOTNOTES, f.footnotes.name)
+	}
+	var n *Note
+	f.footnotes.Notes, n = newNote(f.footnotes.Notes, "footnote", &FootnoteRef{}, f)
+	return n
+}
+
+// addEndnote makes word/endnotes.xml if not exist and adds an empty note
+func (f *Docx) addEndnote() *Note {
+	if f.endnotes == nil {
+		f.endnotes = f.newEndnotes("endnotes.xml")
+		f.addPartRelation(REL_ENDNOTES, f.endnotes.name)
+	}
+	var n *Note
+	f.endnotes.Notes, n = newNote(f.endnotes.Notes, "endnote", &EndnoteRef{}, f)
+	return n
+}
+
+// AddFootnote adds a footnote of text at the end of p and returns
+// the body of the note for further editing
+func (p *Paragraph) AddFootnote(text string) *Note {
+	n := p.file.addFootnote()
+	n.Items[0].(*Paragraph).AddText(" " + text)
+	p.Children = append(p.Children, &Run{
+		RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+		Children:      []interface{}{&FootnoteReference{ID: n.ID}},
+	})
+	return n
+}
+
+// AddEndnote adds an endnote of text at the end of p and returns
+// the body of the note for further editing
+func (p *Paragraph) AddEndnote(text string) *Note {
+	n := p.file.addEndnote()
+	n.Items[0].(*Paragraph).AddText(" " + text)
+	p.Children = append(p.Children, &Run{
+		RunProperties: &RunProperties{VertAlign: &VertAlign{Val: "superscript"}},
+		Children:      []interface{}{&EndnoteReference{ID: n.ID}},
+	})
+	return n
+}
+
+// AddParagraph adds a new paragraph
+func (n *Note) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     n.file,
+	}
+	n.Items = append(n.Items, p)
+	return p
+}
+
+// String returns the plain text of the note
+func (n *Note) String() string {
+	sb := strings.Builder{}
+	for _, it := range n.Items {
+		if p, ok := it.(*Paragraph); ok {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(p.String())
+		}
+	}
+	return sb.String()
+}
+
+// copynotes copies the notes referred in r from f to the file to,
+// and points the references to the new notes
+func (f *Docx) copynotes(r *Run, to *Docx) {
+	if f == nil || f == to {
+		return
+	}
+	f.duplicatenotes(r, to)
+}
+
+// duplicatenotes points the note references in r to new copies
+// of the notes of f in the file to, which can be f itself
+func (f *Docx) duplicatenotes(r *Run, to *Docx) {
+	for i, c := range r.Children {
+		var src, dst *Note
+		switch o := c.(type) {
+		case *FootnoteReference:
+			src = f.footnotes.Note(o.ID)
+			if src == nil {
+				continue
+			}
+			dst = to.addFootnote()
+			r.Children[i] = &FootnoteReference{ID: dst.ID}
+		case *EndnoteReference:
+			src = f.endnotes.Note(o.ID)
+			if src == nil {
+				continue
+			}
+			dst = to.addEndnote()
+			r.Children[i] = &EndnoteReference{ID: dst.ID}
+		default:
+			continue
+		}
+		dst.Items = dst.Items[:0]
+		for _, it := range src.Items {
+			switch o := it.(type) {
+			case *Paragraph:
+				np := o.copymedia(to)
+				dst.Items = append(dst.Items, &np)
+			case *Table:
+				nt := o.copymedia(to)
+				dst.Items = append(dst.Items, &nt)
+			default:
+				dst.Items = append(dst.Items, o)
+			}
+		}
+	}
+}
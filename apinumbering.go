@@ -0,0 +1,242 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strconv"
+)
+
+//nolint:revive,stylecheck
+const (
+	LIST_BULLET       = "bullet"
+	LIST_DECIMAL      = "decimal"
+	LIST_LOWER_LETTER = "lowerLetter"
+	LIST_UPPER_LETTER = "upperLetter"
+	LIST_LOWER_ROMAN  = "lowerRoman"
+	LIST_UPPER_ROMAN  = "upperRoman"
+	LIST_CHINESE      = "chineseCounting"
+)
+
+var (
+	bulletLevelTexts    = [...]string{"●", "○", "■"}
+	decimalLevelFormats = [...]string{LIST_DECIMAL, LIST_LOWER_LETTER, LIST_LOWER_ROMAN}
+)
+
+// numberingTarget finds the file name of numbering part under word/
+// and adds the relationship if add is set and it does not exist
+func (f *Docx) numberingTarget(add bool) string {
+	for _, r := range f.docRelation.Relationship {
+		if r.Type == REL_NUMBERING {
+			return r.Target
+		}
+	}
+	if add {
+		f.addPartRelation(REL_NUMBERING, "numbering.xml")
+	}
+	return "numbering.xml"
+}
+
+// Numbering loads word/numbering.xml from the template or the parsed file on
+// the first call, and the returned numbering will be written back on saving.
+// An empty one will be made if there is no numbering.xml.
+func (f *Docx) Numbering() (*Numbering, error) {
+	if f.numbering != nil {
+		return f.numbering, nil
+	}
+	n := &Numbering{XMLW: XMLNS_W, XMLR: XMLNS_R}
+	target := "word/" + f.numberingTarget(false)
+	for _, name := range f.tmpfslst {
+		if name != target {
+			continue
+		}
+		file, err := f.openTemplateFile(name)
+		if err != nil {
+			return nil, err
+		}
+		err = newPartDecoder(file).Decode(n)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	f.numbering = n
+	return n, nil
+}
+
+// AddList adds a new list of 9 levels, whose numbers look like kind.
+//
+// The levels of LIST_BULLET use ●, ○ and ■ in turns, and those of LIST_DECIMAL
+// use decimal, lowerLetter and lowerRoman in turns. Other kinds use the
+// same format in all levels. Use Num.Level to customize the formats.
+func (f *Docx) AddList(kind string) (*Num, error) {
+	n, err := f.Numbering()
+	if err != nil {
+		return nil, err
+	}
+	a := &AbstractNum{
+		MultiLevelType: &MultiLevelType{Val: "hybridMultilevel"},
+		Levels:         make([]*NumLevel, 9),
+	}
+	for _, x := range n.AbstractNums {
+		if x.AbstractNumID >= a.AbstractNumID {
+			a.AbstractNumID = x.AbstractNumID + 1
+		}
+	}
+	for i := range a.Levels {
+		l := &NumLevel{
+			Ilvl:  i,
+			Start: &NumStart{Val: 1},
+			LvlJc: &LvlJc{Val: "left"},
+		}
+		switch kind {
+		case LIST_BULLET:
+			l.WithFormat(LIST_BULLET, bulletLevelTexts[i%len(bulletLevelTexts)])
+		case LIST_DECIMAL:
+			l.WithFormat(decimalLevelFormats[i%len(decimalLevelFormats)], "%"+strconv.Itoa(i+1)+".")
+		default:
+			l.WithFormat(kind, "%"+strconv.Itoa(i+1)+".")
+		}
+		a.Levels[i] = l.WithIndent(420*(i+1), 420)
+	}
+	n.AbstractNums = append(n.AbstractNums, a)
+	return n.AddNum(a.AbstractNumID), nil
+}
+
+// AbstractNum gets the list definition (or nil on notfound) by abstractNumId
+func (n *Numbering) AbstractNum(id int) *AbstractNum {
+	for _, a := range n.AbstractNums {
+		if a.AbstractNumID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// Num gets the list (or nil on notfound) by numId
+func (n *Numbering) Num(id int) *Num {
+	for _, num := range n.Nums {
+		if num.NumID == id {
+			return num
+		}
+	}
+	return nil
+}
+
+// AddNum adds a new list of the definition abstractNumId
+func (n *Numbering) AddNum(abstractNumID int) *Num {
+	num := &Num{
+		NumID:         1,
+		AbstractNumID: &AbstractNumID{Val: abstractNumID},
+		numbering:     n,
+	}
+	for _, x := range n.Nums {
+		if x.NumID >= num.NumID {
+			num.NumID = x.NumID + 1
+		}
+	}
+	n.Nums = append(n.Nums, num)
+	return num
+}
+
+// Restart returns a new list sharing the same definition with num,
+// whose numbers of level 0 restart from start.
+func (num *Num) Restart(start int) *Num {
+	nn := num.numbering.AddNum(num.AbstractNumID.Val)
+	nn.LvlOverrides = []*LvlOverride{{
+		Ilvl:          0,
+		StartOverride: &StartOverride{Val: start},
+	}}
+	return nn
+}
+
+// Level gets the definition (or nil on notfound) of level in num
+// so that its format could be customized. Notice that all lists
+// sharing the same definition will be changed except the level
+// is overridden in num.
+func (num *Num) Level(level int) *NumLevel {
+	for _, o := range num.LvlOverrides {
+		if o.Ilvl == level && o.Level != nil {
+			return o.Level
+		}
+	}
+	if num.numbering == nil || num.AbstractNumID == nil {
+		return nil
+	}
+	a := num.numbering.AbstractNum(num.AbstractNumID.Val)
+	if a == nil {
+		return nil
+	}
+	for _, l := range a.Levels {
+		if l.Ilvl == level {
+			return l
+		}
+	}
+	return nil
+}
+
+// WithFormat sets the format of number and its text, in which
+// %1 to %9 will be replaced by the numbers of level 0 to 8
+//
+//	numFmt 的取值见 NumFmt
+func (l *NumLevel) WithFormat(numFmt, lvlText string) *NumLevel {
+	l.NumFmt = &NumFmt{Val: numFmt}
+	l.LvlText = &LvlText{Val: lvlText}
+	return l
+}
+
+// WithStart sets the starting value of the level
+func (l *NumLevel) WithStart(start int) *NumLevel {
+	l.Start = &NumStart{Val: start}
+	return l
+}
+
+// WithIndent sets the indent of paragraphs in this level
+//
+// unit: twips (1/20 point)
+func (l *NumLevel) WithIndent(left, hanging int) *NumLevel {
+	if l.ParagraphProperties == nil {
+		l.ParagraphProperties = &ParagraphProperties{}
+	}
+	l.ParagraphProperties.Ind = &Ind{Left: left, Hanging: hanging}
+	return l
+}
+
+// WithFont sets the font of the number, like Symbol or Wingdings for bullets
+func (l *NumLevel) WithFont(font string) *NumLevel {
+	if l.RunProperties == nil {
+		l.RunProperties = &RunProperties{}
+	}
+	l.RunProperties.Fonts = &RunFonts{ASCII: font, HAnsi: font, Hint: "default"}
+	return l
+}
+
+// ListItem makes p an item of num at level (0 to 8)
+func (p *Paragraph) ListItem(num *Num, level int) *Paragraph {
+	if p.Properties == nil {
+		p.Properties = &ParagraphProperties{}
+	}
+	p.Properties.NumPr = &NumPr{
+		Ilvl:  &Ilvl{Val: level},
+		NumID: &NumID{Val: num.NumID},
+	}
+	return p
+}
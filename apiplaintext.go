@@ -0,0 +1,203 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import "strings"
+
+// PlainTextOptions controls the output of Docx.PlainText
+type PlainTextOptions struct {
+	CellSeparator  string // CellSeparator is written between the cells in a row, \t if empty
+	AltText        bool   // AltText writes the alt text of pictures in place
+	SkipHidden     bool   // SkipHidden drops the runs hidden by w:vanish in them or their styles
+	HeadersFooters bool   // HeadersFooters appends the text of headers and footers after body
+}
+
+// plainTextWriter keeps the state of extracting
+type plainTextWriter struct {
+	opts   PlainTextOptions
+	styles *Styles // styles are nil if hidden runs are kept
+	sb     strings.Builder
+}
+
+// PlainText extracts the text in the body, including all the paragraphs
+// in table cells and the text boxes in shapes, groups and canvases.
+//
+// Paragraphs and table rows end with \n, tabs are \t, line breaks are \n
+// and page breaks are \f. The paragraphs in a cell are also split by \n
+// and the text boxes follow the paragraph holding them.
+func (f *Docx) PlainText(opts PlainTextOptions) string {
+	if opts.CellSeparator == "" {
+		opts.CellSeparator = "\t"
+	}
+	pw := &plainTextWriter{opts: opts}
+	if opts.SkipHidden {
+		pw.styles, _ = f.lookupStyles()
+	}
+	pw.items(f.Document.Body.Items)
+	if opts.HeadersFooters {
+		for _, h := range f.headers {
+			pw.items(h.Items)
+		}
+		for _, ft := range f.footers {
+			pw.items(ft.Items)
+		}
+	}
+	return pw.sb.String()
+}
+
+func (pw *plainTextWriter) items(items []interface{}) {
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			pw.inline(o, o.Children, true)
+			pw.sb.WriteByte('\n')
+		case *Table:
+			pw.table(o)
+		}
+	}
+}
+
+func (pw *plainTextWriter) table(t *Table) {
+	for _, tr := range t.TableRows {
+		for i, tc := range tr.TableCells {
+			if i > 0 {
+				pw.sb.WriteString(pw.opts.CellSeparator)
+			}
+			if !tc.vMergeContinued() {
+				pw.cell(tc)
+			}
+			// keep the columns of the grid
+			for k := 1; k < tc.gridSpan(); k++ {
+				pw.sb.WriteString(pw.opts.CellSeparator)
+			}
+		}
+		pw.sb.WriteByte('\n')
+	}
+}
+
+// cell writes the paragraphs in c separated by \n and the nested tables
+func (pw *plainTextWriter) cell(c *WTableCell) {
+	newline := false
+	for _, it := range c.Items {
+		switch o := it.(type) {
+		case *Paragraph:
+			if newline {
+				pw.sb.WriteByte('\n')
+			}
+			pw.inline(o, o.Children, true)
+			newline = true
+		case *Table:
+			if newline {
+				pw.sb.WriteByte('\n')
+			}
+			pw.table(o) // the rows end with \n
+			newline = false
+		}
+	}
+}
+
+// inline writes the children of p, and the text boxes in them if boxes
+func (pw *plainTextWriter) inline(p *Paragraph, children []interface{}, boxes bool) {
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			pw.run(p, o, boxes)
+		case *Hyperlink:
+			if !pw.hidden(p, &o.Run) {
+				pw.sb.WriteString(o.Run.InstrText)
+			}
+			pw.run(p, &o.Run, boxes)
+		case *SimpleField:
+			pw.inline(p, o.Children, boxes)
+		case *Revision:
+			if o.IsInsertion() {
+				pw.inline(p, o.Children, boxes)
+			}
+		}
+	}
+}
+
+// hidden reports whether r should be skipped
+func (pw *plainTextWriter) hidden(p *Paragraph, r *Run) bool {
+	if !pw.opts.SkipHidden {
+		return false
+	}
+	v := (*Vanish)(nil)
+	if pw.styles != nil {
+		v = pw.styles.ResolveRunProperties(p, r).Vanish
+	} else if r.RunProperties != nil {
+		v = r.RunProperties.Vanish
+	}
+	return v != nil && v.Val != "0" && v.Val != "false"
+}
+
+func (pw *plainTextWriter) run(p *Paragraph, r *Run, boxes bool) {
+	if pw.hidden(p, r) {
+		return
+	}
+	for _, c := range r.Children {
+		switch x := c.(type) {
+		case *Text:
+			pw.sb.WriteString(x.Text)
+		case *Tab:
+			pw.sb.WriteByte('\t')
+		case *BarterRabbet:
+			if x.Type == "page" {
+				pw.sb.WriteByte('\f')
+			} else {
+				pw.sb.WriteByte('\n')
+			}
+		case *Drawing:
+			pw.drawing(x, boxes)
+		}
+	}
+}
+
+// drawing writes the alt text of d and its text boxes
+func (pw *plainTextWriter) drawing(d *Drawing, boxes bool) {
+	var g *AGraphic
+	var docpr *WPDocPr
+	if d.Inline != nil {
+		g, docpr = d.Inline.Graphic, d.Inline.DocPr
+	} else if d.Anchor != nil {
+		g, docpr = d.Anchor.Graphic, d.Anchor.DocPr
+	}
+	if pw.opts.AltText && docpr != nil {
+		if docpr.Descr != "" {
+			pw.sb.WriteString(docpr.Descr)
+		} else {
+			pw.sb.WriteString(docpr.Title)
+		}
+	}
+	if !boxes || g == nil || g.GraphicData == nil {
+		return
+	}
+	gd := g.GraphicData
+	// the nested text boxes are visited by walkShapeParagraphs itself
+	walkShapeParagraphs([]interface{}{gd.Shape, gd.Canvas, gd.Group}, func(p *Paragraph) bool {
+		if s := pw.sb.String(); s != "" && s[len(s)-1] != '\n' {
+			pw.sb.WriteByte('\n')
+		}
+		pw.inline(p, p.Children, false)
+		pw.sb.WriteByte('\n')
+		return true
+	})
+}
@@ -0,0 +1,525 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+)
+
+// textPiece is a text or tab in a run that makes up the paragraph text
+type textPiece struct {
+	run  *Run
+	elem interface{} // elem is *Text or *Tab
+	text string
+}
+
+// ReplaceText replaces all old with new in the paragraphs of the body, headers
+// and footers, including those in tables, text boxes, content controls, insertions
+// and simple fields, and returns the count. Breaks are never matched.
+// old can be split into several runs, and new takes the format of the first one.
+func (f *Docx) ReplaceText(old, new string) int {
+	if old == "" {
+		return 0
+	}
+	return f.replace(func(p *Paragraph) int {
+		return p.ReplaceText(old, new)
+	})
+}
+
+// ReplaceRegexp is ReplaceText with the matches of re,
+// and $ in repl is expanded like regexp.Regexp.ReplaceAllString
+func (f *Docx) ReplaceRegexp(re *regexp.Regexp, repl string) int {
+	return f.replace(func(p *Paragraph) int {
+		return p.ReplaceRegexp(re, repl)
+	})
+}
+
+// replace calls fn on all paragraphs and sums the results
+func (f *Docx) replace(fn func(p *Paragraph) int) int {
+	visit := func(p *Paragraph) int {
+		n := fn(p)
+		walkTextBoxParagraphs(p, func(p *Paragraph) bool {
+			n += fn(p)
+			return true
+		})
+		return n
+	}
+	n := f.replaceItems(f.Document.Body.Items, visit)
+	for _, h := range f.headers {
+		n += f.replaceItems(h.Items, visit)
+	}
+	for _, ft := range f.footers {
+		n += f.replaceItems(ft.Items, visit)
+	}
+	return n
+}
+
+// replaceItems calls fn on the paragraphs in items, including those
+// in tables and content controls, and sums the results
+func (f *Docx) replaceItems(items []interface{}, fn func(p *Paragraph) int) int {
+	n := 0
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			n += fn(o)
+		case *Table:
+			for _, tr := range o.TableRows {
+				for _, tc := range tr.TableCells {
+					n += f.replaceItems(tc.Items, fn)
+				}
+			}
+		case *RawXML:
+			n += f.replaceInContentControl(o, true, fn)
+		}
+	}
+	return n
+}
+
+// replaceInContentControl calls fn on the content of r if r is a content
+// control <w:sdt>, which is decoded as body items if block or as the children
+// of a paragraph, and writes the content back if anything is replaced
+func (f *Docx) replaceInContentControl(r *RawXML, block bool, fn func(p *Paragraph) int) int {
+	from, to := r.sdtContent()
+	if from < 0 {
+		return 0
+	}
+	rd := tokenSliceReader(r.tokens[from : to+1])
+	d := xml.NewTokenDecoder(&rd)
+	n := 0
+	var items []interface{}
+	if block {
+		b := Body{file: f}
+		if d.Decode(&b) != nil {
+			return 0
+		}
+		n = f.replaceItems(b.Items, fn)
+		items = b.Items
+	} else {
+		p := Paragraph{file: f}
+		if d.Decode(&p) != nil {
+			return 0
+		}
+		n = fn(&p)
+		items = p.Children
+	}
+	if n == 0 || r.setContent(from, to, items) != nil {
+		return 0
+	}
+	return n
+}
+
+// ReplaceText replaces all old with new in the paragraph and returns the count.
+// old can be split into several runs, and new takes the format of the first one.
+func (p *Paragraph) ReplaceText(old, new string) int {
+	if old == "" {
+		return 0
+	}
+	return p.replace(func(s string) [][]int {
+		var locs [][]int
+		for i := 0; ; {
+			j := strings.Index(s[i:], old)
+			if j < 0 {
+				return locs
+			}
+			locs = append(locs, []int{i + j, i + j + len(old)})
+			i += j + len(old)
+		}
+	}, func(string, []int) string {
+		return new
+	})
+}
+
+// ReplaceRegexp is ReplaceText with the matches of re,
+// and $ in repl is expanded like regexp.Regexp.ReplaceAllString
+func (p *Paragraph) ReplaceRegexp(re *regexp.Regexp, repl string) int {
+	return p.replace(func(s string) [][]int {
+		return re.FindAllStringSubmatchIndex(s, -1)
+	}, func(s string, m []int) string {
+		return string(re.ExpandString(nil, repl, s, m))
+	})
+}
+
+// replace finds the matches in each group of runs whose texts are contiguous
+// and rewrites the pieces covered. The runs of the same format, which Word
+// often splits, are merged by MergeText first if anything matches, so that
+// the replacements are kept in as few runs as possible.
+func (p *Paragraph) replace(find func(s string) [][]int, expand func(s string, m []int) string) int {
+	n := 0
+	for _, c := range p.Children {
+		if r, ok := c.(*RawXML); ok {
+			n += p.file.replaceInContentControl(r, false, func(p *Paragraph) int {
+				return p.replace(find, expand)
+			})
+		}
+	}
+	matched := false
+	for _, group := range textPieceGroups(p.Children) {
+		if s, _ := groupText(group); len(find(s)) > 0 {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return n
+	}
+	p.Children = mergeTextRuns(p.Children)
+	touched := make(map[*Run]struct{}, 8)
+	for _, group := range textPieceGroups(p.Children) {
+		s, starts := groupText(group)
+		locs := find(s)
+		// from the last match so that the indices of the former pieces are kept
+		for i := len(locs) - 1; i >= 0; i-- {
+			m := locs[i]
+			if m[0] == m[1] {
+				insertTextPieces(group, starts, m[0], expand(s, m))
+			} else {
+				replaceTextPieces(group, starts, m[0], m[1], expand(s, m), touched)
+			}
+			n++
+		}
+	}
+	if len(touched) > 0 {
+		p.Children = removeEmptiedRuns(p.Children, touched)
+	}
+	return n
+}
+
+// groupText joins the texts of the pieces and returns
+// it with the start of each piece in it
+func groupText(group []textPiece) (string, []int) {
+	sb := strings.Builder{}
+	starts := make([]int, len(group))
+	for i, pc := range group {
+		starts[i] = sb.Len()
+		sb.WriteString(pc.text)
+	}
+	return sb.String(), starts
+}
+
+// mergeTextRuns merges the runs of the same properties by MergeText. The runs
+// are merged only between the other children like bookmarks, which are kept
+// in place, and the runs of fields are not merged.
+func mergeTextRuns(children []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(children))
+	seg := Paragraph{}
+	flush := func() {
+		if len(seg.Children) > 0 {
+			merged = append(merged, seg.MergeText(MergeSamePropRuns).Children...)
+			seg.Children = nil
+		}
+	}
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			if o.InstrText == "" && !hasFieldChar(o) {
+				seg.Children = append(seg.Children, o)
+				continue
+			}
+		case *RawXML:
+			if o.Name().Local == "proofErr" {
+				seg.Children = append(seg.Children, o)
+				continue
+			}
+		case *Revision:
+			o.Children = mergeTextRuns(o.Children)
+		case *SimpleField:
+			o.Children = mergeTextRuns(o.Children)
+		}
+		flush()
+		merged = append(merged, c)
+	}
+	flush()
+	return merged
+}
+
+// hasFieldChar reports whether r begins, separates or ends a field
+func hasFieldChar(r *Run) bool {
+	for _, x := range r.Children {
+		if _, ok := x.(*FieldChar); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeEmptiedRuns removes the touched runs that have become empty,
+// also in the revisions and simple fields
+func removeEmptiedRuns(children []interface{}, touched map[*Run]struct{}) []interface{} {
+	nc := children[:0]
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			if _, ok := touched[o]; ok && len(o.Children) == 0 {
+				continue
+			}
+		case *Revision:
+			o.Children = removeEmptiedRuns(o.Children, touched)
+		case *SimpleField:
+			o.Children = removeEmptiedRuns(o.Children, touched)
+		}
+		nc = append(nc, c)
+	}
+	return nc
+}
+
+// textPieceGroups splits the texts in the runs of children into groups that
+// are not separated by fields, links, breaks, drawings or other contents.
+// The texts of a link, an insertion and a simple field are groups of their own.
+// The breaks are not matched so that a pattern of "\n" will not remove them.
+func textPieceGroups(children []interface{}) [][]textPiece {
+	groups := make([][]textPiece, 0, 4)
+	var group []textPiece
+	cut := func() {
+		if len(group) > 0 {
+			groups = append(groups, group)
+			group = nil
+		}
+	}
+	addRun := func(r *Run) {
+		for _, x := range r.Children {
+			switch e := x.(type) {
+			case *Text:
+				group = append(group, textPiece{run: r, elem: e, text: e.Text})
+			case *Tab:
+				group = append(group, textPiece{run: r, elem: e, text: "\t"})
+			default:
+				cut()
+			}
+		}
+	}
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			if o.InstrText != "" {
+				cut()
+				continue
+			}
+			addRun(o)
+		case *Hyperlink:
+			cut()
+			addRun(&o.Run)
+			cut()
+		case *Revision:
+			cut()
+			if o.IsInsertion() {
+				groups = append(groups, textPieceGroups(o.Children)...)
+			}
+		case *SimpleField:
+			cut()
+			groups = append(groups, textPieceGroups(o.Children)...)
+		case *BookmarkStart, *BookmarkEnd, *CommentRangeStart, *CommentRangeEnd, *RawXML:
+			// marks between runs do not separate the text
+		default:
+			cut()
+		}
+	}
+	cut()
+	return groups
+}
+
+// insertTextPieces inserts repl at the position at of the group for an
+// empty match, after the piece ending there or before the one starting there
+func insertTextPieces(group []textPiece, starts []int, at int, repl string) {
+	for i, pc := range group {
+		start := starts[i]
+		if at > start+len(pc.text) {
+			continue
+		}
+		idx := indexOf(pc.run.Children, pc.elem)
+		if idx < 0 {
+			return
+		}
+		nc := textChildren(repl)
+		if t, ok := pc.elem.(*Text); ok && at > start && at-start < len(t.Text) {
+			nc = append(nc, newText(t.Text[at-start:]))
+			t.Text = t.Text[:at-start]
+			if strings.TrimSpace(t.Text) != t.Text {
+				t.XMLSpace = "preserve"
+			}
+			idx++
+		} else if at > start {
+			idx++
+		}
+		children := make([]interface{}, 0, len(pc.run.Children)+len(nc))
+		children = append(children, pc.run.Children[:idx]...)
+		children = append(children, nc...)
+		children = append(children, pc.run.Children[idx:]...)
+		pc.run.Children = children
+		return
+	}
+}
+
+// replaceTextPieces replaces the text in [from, to) of the group with repl,
+// which is put at the place of the first piece covered
+func replaceTextPieces(group []textPiece, starts []int, from, to int, repl string, touched map[*Run]struct{}) {
+	first := true
+	for i, pc := range group {
+		start, end := starts[i], starts[i]+len(pc.text)
+		if end <= from || start >= to {
+			if start >= to {
+				break
+			}
+			continue
+		}
+		idx := -1
+		for j, x := range pc.run.Children {
+			if x == pc.elem {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		// the text before the match is kept in the same element so that
+		// the former matches in it can still find it
+		keep := false
+		var nc []interface{}
+		if t, ok := pc.elem.(*Text); ok {
+			suffix := ""
+			if end > to {
+				suffix = t.Text[to-start:]
+			}
+			if start < from {
+				t.Text = t.Text[:from-start]
+				if strings.TrimSpace(t.Text) != t.Text {
+					t.XMLSpace = "preserve"
+				}
+				keep = true
+			}
+			if first {
+				nc = append(nc, textChildren(repl)...)
+			}
+			if suffix != "" {
+				nc = append(nc, newText(suffix))
+			}
+		} else if first {
+			nc = textChildren(repl)
+		}
+		first = false
+		if keep {
+			idx++
+		}
+		children := make([]interface{}, 0, len(pc.run.Children)+len(nc))
+		children = append(children, pc.run.Children[:idx]...)
+		children = append(children, nc...)
+		if keep {
+			children = append(children, pc.run.Children[idx:]...)
+		} else {
+			children = append(children, pc.run.Children[idx+1:]...)
+		}
+		pc.run.Children = children
+		touched[pc.run] = struct{}{}
+	}
+}
+
+// newText makes a text that keeps its leading and trailing spaces
+func newText(s string) *Text {
+	t := &Text{Text: s}
+	if strings.TrimSpace(s) != s {
+		t.XMLSpace = "preserve"
+	}
+	return t
+}
+
+// textChildren splits s into texts, tabs and breaks like AddText
+func textChildren(s string) []interface{} {
+	c := make([]interface{}, 0, 4)
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			c = append(c, &BarterRabbet{})
+		}
+		for j, k := range strings.Split(line, "\t") {
+			if j > 0 {
+				c = append(c, &Tab{})
+			}
+			if k != "" {
+				c = append(c, newText(k))
+			}
+		}
+	}
+	return c
+}
+
+// walkTextBoxParagraphs visits the paragraphs in the text boxes
+// of the drawings in p
+func walkTextBoxParagraphs(p *Paragraph, fn func(p *Paragraph) bool) bool {
+	for _, c := range p.Children {
+		r, ok := c.(*Run)
+		if !ok {
+			continue
+		}
+		for _, x := range r.Children {
+			d, ok := x.(*Drawing)
+			if !ok {
+				continue
+			}
+			var g *AGraphic
+			if d.Inline != nil {
+				g = d.Inline.Graphic
+			} else if d.Anchor != nil {
+				g = d.Anchor.Graphic
+			}
+			if g == nil || g.GraphicData == nil {
+				continue
+			}
+			gd := g.GraphicData
+			if !walkShapeParagraphs([]interface{}{gd.Shape, gd.Canvas, gd.Group}, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// walkShapeParagraphs visits the paragraphs in the text boxes of the shapes
+// in canvases and groups recursively
+func walkShapeParagraphs(elems []interface{}, fn func(p *Paragraph) bool) bool {
+	for _, e := range elems {
+		switch o := e.(type) {
+		case *WordprocessingShape:
+			if o == nil || o.TextBox == nil || o.TextBox.Content == nil {
+				continue
+			}
+			for i := range o.TextBox.Content.Paragraphs {
+				p := &o.TextBox.Content.Paragraphs[i]
+				if !fn(p) || !walkTextBoxParagraphs(p, fn) {
+					return false
+				}
+			}
+		case *WordprocessingCanvas:
+			if o != nil && !walkShapeParagraphs(o.Items, fn) {
+				return false
+			}
+		case *WordprocessingGroup:
+			if o != nil && !walkShapeParagraphs(o.Elems, fn) {
+				return false
+			}
+		case *WPGGroupShape:
+			if o != nil && !walkShapeParagraphs(o.Elems, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,402 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"sync/atomic"
+	"time"
+)
+
+// Revisions returns all the insertions, deletions and moves in the document body,
+// headers, footers, notes and comments
+func (f *Docx) Revisions() []*Revision {
+	revs := make([]*Revision, 0, 16)
+	for _, items := range f.partItems() {
+		walkParagraphs(*items, func(p *Paragraph) bool {
+			revs = appendRevisions(revs, p.Children)
+			return true
+		})
+	}
+	return revs
+}
+
+// partItems returns the items of the document body, headers, footers,
+// notes and comments
+func (f *Docx) partItems() []*[]interface{} {
+	parts := make([]*[]interface{}, 0, 1+len(f.headers)+len(f.footers))
+	parts = append(parts, &f.Document.Body.Items)
+	for _, h := range f.headers {
+		parts = append(parts, &h.Items)
+	}
+	for _, ft := range f.footers {
+		parts = append(parts, &ft.Items)
+	}
+	if f.footnotes != nil {
+		for _, n := range f.footnotes.Notes {
+			parts = append(parts, &n.Items)
+		}
+	}
+	if f.endnotes != nil {
+		for _, n := range f.endnotes.Notes {
+			parts = append(parts, &n.Items)
+		}
+	}
+	if f.comments != nil {
+		for _, c := range f.comments.Comments {
+			parts = append(parts, &c.Items)
+		}
+	}
+	return parts
+}
+
+func appendRevisions(revs []*Revision, children []interface{}) []*Revision {
+	for _, c := range children {
+		if rv, ok := c.(*Revision); ok {
+			revs = append(revs, rv)
+			revs = appendRevisions(revs, rv.Children)
+		}
+	}
+	return revs
+}
+
+// AcceptAllRevisions accepts all the tracked changes in the document
+// and returns the number of them
+func (f *Docx) AcceptAllRevisions() int {
+	return f.resolveRevisions(true, func(int) bool { return true })
+}
+
+// RejectAllRevisions rejects all the tracked changes in the document
+// and returns the number of them
+func (f *Docx) RejectAllRevisions() int {
+	return f.resolveRevisions(false, func(int) bool { return true })
+}
+
+// AcceptRevision accepts the tracked change of id, which can be a Revision,
+// a RevisionMark or a properties change, and reports whether it is found
+func (f *Docx) AcceptRevision(id int) bool {
+	return f.resolveRevisions(true, func(x int) bool { return x == id }) > 0
+}
+
+// RejectRevision rejects the tracked change of id, which can be a Revision,
+// a RevisionMark or a properties change, and reports whether it is found
+func (f *Docx) RejectRevision(id int) bool {
+	return f.resolveRevisions(false, func(x int) bool { return x == id }) > 0
+}
+
+// resolveRevisions accepts or rejects the tracked changes whose id matches
+// in all the parts and returns the number of them
+func (f *Docx) resolveRevisions(accept bool, match func(id int) bool) (n int) {
+	for _, items := range f.partItems() {
+		var m int
+		*items, m = resolveRevisionsIn(*items, accept, match)
+		n += m
+	}
+	return
+}
+
+// resolveRevisionsIn accepts or rejects the tracked changes whose id matches
+// in items and returns the new items and the number of them
+func resolveRevisionsIn(items []interface{}, accept bool, match func(id int) bool) ([]interface{}, int) {
+	n := 0
+	join := make(map[*Paragraph]struct{}, 8)
+	walkParagraphs(items, func(p *Paragraph) bool {
+		var m int
+		if pp := p.Properties; pp != nil {
+			if c := pp.Change; c != nil && match(c.ID) {
+				if !accept && c.Properties != nil {
+					np := *c.Properties
+					np.RunProperties = pp.RunProperties
+					np.SectPr = pp.SectPr
+					p.Properties = &np
+				}
+				p.Properties.Change = nil
+				n++
+			}
+			if rp := p.Properties.RunProperties; rp != nil {
+				// a removed paragraph mark joins the paragraph with the next one
+				if rp.Ins != nil && match(rp.Ins.ID) {
+					if !accept {
+						join[p] = struct{}{}
+					}
+					rp.Ins = nil
+					n++
+				}
+				if rp.Del != nil && match(rp.Del.ID) {
+					if accept {
+						join[p] = struct{}{}
+					}
+					rp.Del = nil
+					n++
+				}
+				p.Properties.RunProperties, m = resolveRunProperties(rp, accept, match)
+				n += m
+			}
+		}
+		p.Children, m = resolveRevisionsOf(p.Children, accept, match)
+		n += m
+		return true
+	})
+	if len(join) > 0 {
+		items = joinParagraphs(items, join)
+	}
+	return items, n
+}
+
+// resolveRunProperties accepts or rejects the change of rp if its id matches
+func resolveRunProperties(rp *RunProperties, accept bool, match func(id int) bool) (*RunProperties, int) {
+	if rp == nil || rp.Change == nil || !match(rp.Change.ID) {
+		return rp, 0
+	}
+	if accept || rp.Change.RunProperties == nil {
+		rp.Change = nil
+		if accept {
+			return rp, 1
+		}
+		return &RunProperties{Ins: rp.Ins, Del: rp.Del}, 1
+	}
+	old := *rp.Change.RunProperties
+	old.Ins, old.Del, old.Change = rp.Ins, rp.Del, nil
+	return &old, 1
+}
+
+// resolveRevisionsOf accepts or rejects the matched changes in the children of a paragraph
+func resolveRevisionsOf(children []interface{}, accept bool, match func(id int) bool) ([]interface{}, int) {
+	n := 0
+	nc := make([]interface{}, 0, len(children))
+	for _, c := range children {
+		var m int
+		switch o := c.(type) {
+		case *Revision:
+			o.Children, m = resolveRevisionsOf(o.Children, accept, match)
+			n += m
+			if !match(o.ID) {
+				nc = append(nc, o)
+				continue
+			}
+			n++
+			if o.IsInsertion() != accept {
+				continue // the content is removed
+			}
+			if !o.IsInsertion() {
+				undelete(o.Children)
+			}
+			nc = append(nc, o.Children...)
+			continue
+		case *Run:
+			o.RunProperties, m = resolveRunProperties(o.RunProperties, accept, match)
+			n += m
+		case *Hyperlink:
+			o.Run.RunProperties, m = resolveRunProperties(o.Run.RunProperties, accept, match)
+			n += m
+		case *RawXML:
+			switch o.Name().Local {
+			case "moveFromRangeStart", "moveFromRangeEnd", "moveToRangeStart", "moveToRangeEnd":
+				id, err := getAttInt(o.tokens[0].(xml.StartElement).Attr, "id")
+				if err == nil && match(id) {
+					continue
+				}
+			}
+		}
+		nc = append(nc, c)
+	}
+	return nc, n
+}
+
+// undelete turns the w:delText and w:delInstrText in the runs of children
+// into w:t and w:instrText
+func undelete(children []interface{}) {
+	for _, c := range children {
+		var r *Run
+		switch o := c.(type) {
+		case *Run:
+			r = o
+		case *Hyperlink:
+			r = &o.Run
+		default:
+			continue
+		}
+		for i, x := range r.Children {
+			if dt, ok := x.(*DelText); ok {
+				r.Children[i] = &Text{XMLSpace: dt.XMLSpace, Text: dt.Text}
+			}
+		}
+		r.InstrText += r.DelInstrText
+		r.DelInstrText = ""
+	}
+}
+
+// joinParagraphs moves the children of each paragraph in join
+// to the head of the next paragraph and removes it
+func joinParagraphs(items []interface{}, join map[*Paragraph]struct{}) []interface{} {
+	ni := make([]interface{}, 0, len(items))
+	var pending *Paragraph
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			if pending != nil {
+				o.Children = append(pending.Children, o.Children...)
+				pending = nil
+			}
+			if _, ok := join[o]; ok {
+				pending = o
+				continue
+			}
+		case *Table:
+			for _, tr := range o.TableRows {
+				for _, tc := range tr.TableCells {
+					tc.Items = joinParagraphs(tc.Items, join)
+				}
+			}
+		}
+		if pending != nil { // no paragraph to join in
+			ni = append(ni, pending)
+			pending = nil
+		}
+		ni = append(ni, it)
+	}
+	if pending != nil {
+		ni = append(ni, pending)
+	}
+	return ni
+}
+
+// nextRevisionID returns an unused id of tracked changes
+func (f *Docx) nextRevisionID() int {
+	return int(atomic.AddUintptr(&f.revID, 1) - 1)
+}
+
+// seedRevisionID returns the id after all the ids of tracked changes
+// in all the parts of the parsed document
+func (f *Docx) seedRevisionID() int {
+	id := 0
+	use := func(x int) {
+		if x >= id {
+			id = x + 1
+		}
+	}
+	usep := func(rp *RunProperties) {
+		if rp == nil {
+			return
+		}
+		if rp.Ins != nil {
+			use(rp.Ins.ID)
+		}
+		if rp.Del != nil {
+			use(rp.Del.ID)
+		}
+		if rp.Change != nil {
+			use(rp.Change.ID)
+		}
+	}
+	var usec func(children []interface{})
+	usec = func(children []interface{}) {
+		for _, c := range children {
+			switch o := c.(type) {
+			case *Revision:
+				use(o.ID)
+				usec(o.Children)
+			case *Run:
+				usep(o.RunProperties)
+			case *Hyperlink:
+				usep(o.Run.RunProperties)
+			case *RawXML:
+				switch o.Name().Local {
+				case "moveFromRangeStart", "moveFromRangeEnd", "moveToRangeStart", "moveToRangeEnd":
+					if x, err := getAttInt(o.tokens[0].(xml.StartElement).Attr, "id"); err == nil {
+						use(x)
+					}
+				}
+			}
+		}
+	}
+	visit := func(p *Paragraph) bool {
+		if p.Properties != nil {
+			if p.Properties.Change != nil {
+				use(p.Properties.Change.ID)
+			}
+			usep(p.Properties.RunProperties)
+		}
+		usec(p.Children)
+		return true
+	}
+	for _, items := range f.partItems() {
+		walkParagraphs(*items, visit)
+	}
+	return id
+}
+
+// newRevision makes a tracked change of kind by author with an unused id
+func (f *Docx) newRevision(kind, author string) *Revision {
+	return &Revision{
+		XMLName:  xml.Name{Local: "w:" + kind},
+		ID:       f.nextRevisionID(),
+		Author:   author,
+		Date:     time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Children: make([]interface{}, 0, 4),
+		file:     f,
+	}
+}
+
+// AddInsertedText adds text at the end of p as a tracked insertion by author
+func (p *Paragraph) AddInsertedText(text, author string) *Run {
+	r := p.AddText(text)
+	p.Children = p.Children[:len(p.Children)-1]
+	rv := p.file.newRevision(REVISION_INS, author)
+	rv.Children = append(rv.Children, r)
+	p.Children = append(p.Children, rv)
+	return r
+}
+
+// TrackInsertion marks the run r in p as inserted by author,
+// and returns nil if r is not in p
+func (p *Paragraph) TrackInsertion(r *Run, author string) *Revision {
+	return p.track(REVISION_INS, r, author)
+}
+
+// TrackDeletion marks the run r in p as deleted by author,
+// and returns nil if r is not in p
+func (p *Paragraph) TrackDeletion(r *Run, author string) *Revision {
+	rv := p.track(REVISION_DEL, r, author)
+	if rv == nil {
+		return nil
+	}
+	for i, x := range r.Children {
+		if t, ok := x.(*Text); ok {
+			r.Children[i] = &DelText{XMLSpace: t.XMLSpace, Text: t.Text}
+		}
+	}
+	r.DelInstrText += r.InstrText
+	r.InstrText = ""
+	return rv
+}
+
+func (p *Paragraph) track(kind string, r *Run, author string) *Revision {
+	for i, c := range p.Children {
+		if c == r {
+			rv := p.file.newRevision(kind, author)
+			rv.Children = append(rv.Children, r)
+			p.Children[i] = rv
+			return rv
+		}
+	}
+	return nil
+}
@@ -58,6 +58,12 @@ func (r *Run) Italic() *Run {
 	return r
 }
 
+// Strike ...
+func (r *Run) Strike() *Run {
+	r.RunProperties.Strike = &Strike{Val: "true"}
+	return r
+}
+
 // Underline has several possible values including
 //
 //	none: Specifies that no underline should be applied.
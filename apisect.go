@@ -0,0 +1,246 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// lastSectPr finds the properties of the last section in body, or nil
+func (f *Docx) lastSectPr() *SectPr {
+	for i := len(f.Document.Body.Items) - 1; i >= 0; i-- {
+		if sect, ok := f.Document.Body.Items[i].(*SectPr); ok {
+			return sect
+		}
+	}
+	return nil
+}
+
+// SectPr returns the properties of the last section in body,
+// and a new one will be appended to body if not exist.
+func (f *Docx) SectPr() *SectPr {
+	sect := f.lastSectPr()
+	if sect == nil {
+		sect = &SectPr{}
+		f.Document.Body.Items = append(f.Document.Body.Items, sect)
+	}
+	return sect
+}
+
+// AddSectionBreak ends the current section at a new empty paragraph
+// and returns the properties of the next section, which starts as typ.
+//
+//	typ 的取值见 SectType
+func (f *Docx) AddSectionBreak(typ string) *SectPr {
+	f.AddParagraph().EndSection()
+	return f.SectPr().WithType(typ)
+}
+
+// EndSection makes p the last paragraph of a section and returns the
+// properties of the section, which are copied from the last section
+// of the document if p doesn't have one.
+func (p *Paragraph) EndSection() *SectPr {
+	if p.Properties == nil {
+		p.Properties = &ParagraphProperties{}
+	}
+	if p.Properties.SectPr != nil {
+		return p.Properties.SectPr
+	}
+	var sect *SectPr
+	if p.file != nil {
+		sect = p.file.lastSectPr()
+	}
+	if sect == nil {
+		p.Properties.SectPr = &SectPr{}
+	} else {
+		p.Properties.SectPr = sect.clone()
+	}
+	return p.Properties.SectPr
+}
+
+// clone deep-copies the section properties
+func (sect *SectPr) clone() *SectPr {
+	n := &SectPr{RsidR: sect.RsidR, RsidRPr: sect.RsidRPr, RsidDel: sect.RsidDel, RsidSect: sect.RsidSect}
+	for _, ref := range sect.HeaderReferences {
+		r := *ref
+		n.HeaderReferences = append(n.HeaderReferences, &r)
+	}
+	for _, ref := range sect.FooterReferences {
+		r := *ref
+		n.FooterReferences = append(n.FooterReferences, &r)
+	}
+	if sect.Type != nil {
+		v := *sect.Type
+		n.Type = &v
+	}
+	if sect.PgSz != nil {
+		v := *sect.PgSz
+		n.PgSz = &v
+	}
+	if sect.PgMar != nil {
+		v := *sect.PgMar
+		n.PgMar = &v
+	}
+	if sect.LnNumType != nil {
+		v := *sect.LnNumType
+		n.LnNumType = &v
+	}
+	if sect.PgNumType != nil {
+		v := *sect.PgNumType
+		if v.Start != nil {
+			start := *v.Start
+			v.Start = &start
+		}
+		n.PgNumType = &v
+	}
+	if sect.Cols != nil {
+		v := *sect.Cols
+		v.Cols = make([]*Col, len(sect.Cols.Cols))
+		for i, c := range sect.Cols.Cols {
+			col := *c
+			v.Cols[i] = &col
+		}
+		n.Cols = &v
+	}
+	if sect.VAlign != nil {
+		v := *sect.VAlign
+		n.VAlign = &v
+	}
+	if sect.TitlePg != nil {
+		v := *sect.TitlePg
+		n.TitlePg = &v
+	}
+	if sect.DocGrid != nil {
+		v := *sect.DocGrid
+		n.DocGrid = &v
+	}
+	for _, o := range sect.Others {
+		n.Others = append(n.Others, o.clone())
+	}
+	return n
+}
+
+// WithType sets how the section starts
+//
+//	typ 的取值见 SectType
+func (sect *SectPr) WithType(typ string) *SectPr {
+	sect.Type = &SectType{Val: typ}
+	return sect
+}
+
+// WithPageSize sets the paper size
+//
+// unit: twips (1/20 point)
+func (sect *SectPr) WithPageSize(w, h int) *SectPr {
+	if sect.PgSz == nil {
+		sect.PgSz = &PgSz{}
+	}
+	sect.PgSz.W = xml.Attr{Name: xml.Name{Local: "w:w"}, Value: strconv.Itoa(w)}
+	sect.PgSz.H = xml.Attr{Name: xml.Name{Local: "w:h"}, Value: strconv.Itoa(h)}
+	return sect
+}
+
+// WithOrientation sets the paper to portrait or landscape,
+// swapping its width and height if needed. The paper will
+// be A4 if the size has not been set.
+func (sect *SectPr) WithOrientation(orient string) *SectPr {
+	if sect.PgSz == nil || sect.PgSz.W.Value == "" || sect.PgSz.H.Value == "" {
+		sect.WithPageSize(11906, 16838)
+	}
+	w, _ := GetInt(sect.PgSz.W.Value)
+	h, _ := GetInt(sect.PgSz.H.Value)
+	if (orient == "landscape") == (w < h) {
+		sect.WithPageSize(h, w)
+	}
+	sect.PgSz.Orient = xml.Attr{Name: xml.Name{Local: "w:orient"}, Value: orient}
+	return sect
+}
+
+// WithMargins sets the page margins. The distances of header
+// and footer will be 720 if they have not been set.
+//
+// unit: twips (1/20 point)
+func (sect *SectPr) WithMargins(top, right, bottom, left int) *SectPr {
+	if sect.PgMar == nil {
+		sect.PgMar = &PgMar{Header: 720, Footer: 720}
+	}
+	sect.PgMar.Top = top
+	sect.PgMar.Right = right
+	sect.PgMar.Bottom = bottom
+	sect.PgMar.Left = left
+	return sect
+}
+
+// WithColumns splits the section into num columns of equal width
+// with space between them
+//
+// unit: twips (1/20 point)
+func (sect *SectPr) WithColumns(num, space int) *SectPr {
+	sect.Cols = &Cols{Num: num, Space: space}
+	return sect
+}
+
+// WithPageNumbering sets the format of page numbers and
+// restarts them from start if start is not negative
+//
+//	format 的取值见 PgNumType
+func (sect *SectPr) WithPageNumbering(format string, start int) *SectPr {
+	sect.PgNumType = &PgNumType{Fmt: format}
+	if start >= 0 {
+		sect.PgNumType.Start = &start
+	}
+	return sect
+}
+
+// WithLineNumbering shows the number every countBy lines
+//
+//	restart 的取值见 LnNumType
+func (sect *SectPr) WithLineNumbering(countBy, start, distance int, restart string) *SectPr {
+	sect.LnNumType = &LnNumType{CountBy: countBy, Start: start, Distance: distance, Restart: restart}
+	return sect
+}
+
+// WithVAlign sets the vertical alignment of text on pages
+//
+//	val 的取值可以是以下之一：
+//		top：顶端对齐（默认）。
+//		center：居中。
+//		both：两端对齐。
+//		bottom：底端对齐。
+func (sect *SectPr) WithVAlign(val string) *SectPr {
+	sect.VAlign = &WVerticalAlignment{Val: val}
+	return sect
+}
+
+// WithTitlePage enables the first page header and footer
+func (sect *SectPr) WithTitlePage() *SectPr {
+	sect.TitlePg = &TitlePg{}
+	return sect
+}
+
+// WithDocGrid sets the grid of lines and characters
+//
+//	typ 的取值见 DocGrid
+func (sect *SectPr) WithDocGrid(typ string, linePitch, charSpace int) *SectPr {
+	sect.DocGrid = &DocGrid{Type: typ, LinePitch: linePitch, CharSpace: charSpace}
+	return sect
+}
@@ -0,0 +1,435 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"reflect"
+)
+
+// Styles loads word/styles.xml from the template or the parsed file on
+// the first call, and the returned styles will be written back on saving.
+// An empty one will be made if there is no styles.xml.
+func (f *Docx) Styles() (*Styles, error) {
+	if f.styles == nil {
+		s, err := f.lookupStyles()
+		if err != nil {
+			return nil, err
+		}
+		f.styles = s
+	}
+	return f.styles, nil
+}
+
+// lookupStyles loads the styles like Styles, but only for reading,
+// so that they will not be written back on saving
+func (f *Docx) lookupStyles() (*Styles, error) {
+	if f.styles != nil {
+		return f.styles, nil
+	}
+	if f.readStyles != nil {
+		return f.readStyles, nil
+	}
+	s := &Styles{XMLW: XMLNS_W, XMLR: XMLNS_R, file: f}
+	for _, name := range f.tmpfslst {
+		if name != "word/styles.xml" {
+			continue
+		}
+		file, err := f.openTemplateFile(name)
+		if err != nil {
+			return nil, err
+		}
+		err = newPartDecoder(file).Decode(s)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	f.readStyles = s
+	return s, nil
+}
+
+// Style gets the style (or nil on notfound) by styleId
+func (s *Styles) Style(id string) *StyleDefinition {
+	for _, sd := range s.Styles {
+		if sd.StyleID == id {
+			return sd
+		}
+	}
+	return nil
+}
+
+// StyleByName gets the style (or nil on notfound) by the name shown in Word,
+// like Normal and heading 1
+func (s *Styles) StyleByName(name string) *StyleDefinition {
+	for _, sd := range s.Styles {
+		if sd.Name != nil && sd.Name.Val == name {
+			return sd
+		}
+	}
+	return nil
+}
+
+// Default gets the default style (or nil on notfound) of typ
+//
+//	typ 的取值见 StyleDefinition
+func (s *Styles) Default(typ string) *StyleDefinition {
+	for _, sd := range s.Styles {
+		if sd.Type == typ && (sd.Default == "1" || sd.Default == "true") {
+			return sd
+		}
+	}
+	return nil
+}
+
+// AddStyle adds a new style of typ, or returns the existing one with the same id
+//
+//	typ 的取值见 StyleDefinition
+func (s *Styles) AddStyle(typ, id, name string) *StyleDefinition {
+	if sd := s.Style(id); sd != nil {
+		return sd
+	}
+	sd := &StyleDefinition{
+		Type:        typ,
+		CustomStyle: "1",
+		StyleID:     id,
+		Name:        &StyleName{Val: name},
+	}
+	s.Styles = append(s.Styles, sd)
+	return sd
+}
+
+// WithBasedOn sets the parent style
+func (sd *StyleDefinition) WithBasedOn(id string) *StyleDefinition {
+	sd.BasedOn = &BasedOn{Val: id}
+	return sd
+}
+
+// WithNext sets the style of the next new paragraph
+func (sd *StyleDefinition) WithNext(id string) *StyleDefinition {
+	sd.Next = &NextStyle{Val: id}
+	return sd
+}
+
+// WithLink links the paragraph style and the character style
+func (sd *StyleDefinition) WithLink(id string) *StyleDefinition {
+	sd.Link = &LinkStyle{Val: id}
+	return sd
+}
+
+// WithQFormat shows the style in the style gallery
+func (sd *StyleDefinition) WithQFormat() *StyleDefinition {
+	sd.QFormat = &QFormat{}
+	return sd
+}
+
+// WithUIPriority sets the sort order in Word
+func (sd *StyleDefinition) WithUIPriority(priority int) *StyleDefinition {
+	sd.UIPriority = &UIPriority{Val: priority}
+	return sd
+}
+
+func (sd *StyleDefinition) runProperties() *RunProperties {
+	if sd.RunProperties == nil {
+		sd.RunProperties = &RunProperties{}
+	}
+	return sd.RunProperties
+}
+
+func (sd *StyleDefinition) paragraphProperties() *ParagraphProperties {
+	if sd.ParagraphProperties == nil {
+		sd.ParagraphProperties = &ParagraphProperties{}
+	}
+	return sd.ParagraphProperties
+}
+
+// Bold ...
+func (sd *StyleDefinition) Bold() *StyleDefinition {
+	sd.runProperties().Bold = &Bold{}
+	return sd
+}
+
+// Italic ...
+func (sd *StyleDefinition) Italic() *StyleDefinition {
+	sd.runProperties().Italic = &Italic{}
+	return sd
+}
+
+// Color allows to set the text color of the style
+func (sd *StyleDefinition) Color(color string) *StyleDefinition {
+	sd.runProperties().Color = &Color{Val: color}
+	return sd
+}
+
+// Size allows to set the text size of the style
+func (sd *StyleDefinition) Size(size string) *StyleDefinition {
+	sd.runProperties().Size = &Size{Val: size}
+	return sd
+}
+
+// Font sets the font of the style
+func (sd *StyleDefinition) Font(ascii, hansi, hint string) *StyleDefinition {
+	sd.runProperties().Fonts = &RunFonts{
+		ASCII: ascii,
+		HAnsi: hansi,
+		Hint:  hint,
+	}
+	return sd
+}
+
+// Justification allows to set the horizonal alignment of the style
+//
+//	w:jc 属性的取值见 Paragraph.Justification
+func (sd *StyleDefinition) Justification(val string) *StyleDefinition {
+	sd.paragraphProperties().Justification = &Justification{Val: val}
+	return sd
+}
+
+// Conditional gets the conditional formatting of typ in the table style,
+// which will be added if not exist
+//
+//	typ 的取值见 TableStyleProperties
+func (sd *StyleDefinition) Conditional(typ string) *TableStyleProperties {
+	for _, tp := range sd.TableStyleProperties {
+		if tp.Type == typ {
+			return tp
+		}
+	}
+	tp := &TableStyleProperties{Type: typ}
+	sd.TableStyleProperties = append(sd.TableStyleProperties, tp)
+	return tp
+}
+
+// TableBorders sets the borders of the tables in the table style
+func (sd *StyleDefinition) TableBorders(borders *WTableBorders) *StyleDefinition {
+	if sd.TableProperties == nil {
+		sd.TableProperties = &WTableProperties{}
+	}
+	sd.TableProperties.TableBorders = borders
+	return sd
+}
+
+func (tp *TableStyleProperties) runProperties() *RunProperties {
+	if tp.RunProperties == nil {
+		tp.RunProperties = &RunProperties{}
+	}
+	return tp.RunProperties
+}
+
+// Bold ...
+func (tp *TableStyleProperties) Bold() *TableStyleProperties {
+	tp.runProperties().Bold = &Bold{}
+	return tp
+}
+
+// Italic ...
+func (tp *TableStyleProperties) Italic() *TableStyleProperties {
+	tp.runProperties().Italic = &Italic{}
+	return tp
+}
+
+// Color allows to set the text color of the cells
+func (tp *TableStyleProperties) Color(color string) *TableStyleProperties {
+	tp.runProperties().Color = &Color{Val: color}
+	return tp
+}
+
+// Shade allows to set the shade of the cells
+func (tp *TableStyleProperties) Shade(val, color, fill string) *TableStyleProperties {
+	if tp.TableCellProperties == nil {
+		tp.TableCellProperties = &WTableCellProperties{}
+	}
+	tp.TableCellProperties.Shade = &Shade{
+		Val:   val,
+		Color: color,
+		Fill:  fill,
+	}
+	return tp
+}
+
+// Style applies the paragraph style of styleId id
+func (p *Paragraph) Style(id string) *Paragraph {
+	if p.Properties == nil {
+		p.Properties = &ParagraphProperties{}
+	}
+	p.Properties.Style = &Style{Val: id}
+	return p
+}
+
+// Style applies the character style of styleId id
+func (r *Run) Style(id string) *Run {
+	if r.RunProperties == nil {
+		r.RunProperties = &RunProperties{}
+	}
+	r.RunProperties.RunStyle = &RunStyle{Val: id}
+	return r
+}
+
+// chain returns the style of id and its ancestors, the root comes first
+func (s *Styles) chain(id string) []*StyleDefinition {
+	var lst []*StyleDefinition
+	seen := make(map[string]struct{}, 8)
+	for id != "" {
+		if _, ok := seen[id]; ok {
+			break // loop in basedOn
+		}
+		seen[id] = struct{}{}
+		sd := s.Style(id)
+		if sd == nil {
+			break
+		}
+		lst = append(lst, sd)
+		id = ""
+		if sd.BasedOn != nil {
+			id = sd.BasedOn.Val
+		}
+	}
+	for i, j := 0, len(lst)-1; i < j; i, j = i+1, j-1 {
+		lst[i], lst[j] = lst[j], lst[i]
+	}
+	return lst
+}
+
+// paragraphStyleChain returns the paragraph style chain of p, the root comes first
+func (s *Styles) paragraphStyleChain(p *Paragraph) []*StyleDefinition {
+	if p != nil && p.Properties != nil && p.Properties.Style != nil {
+		return s.chain(p.Properties.Style.Val)
+	}
+	if sd := s.Default(STYLE_TYPE_PARAGRAPH); sd != nil {
+		return s.chain(sd.StyleID)
+	}
+	return nil
+}
+
+// overlayProperties copies each non-empty field of src into dst,
+// both of which are pointers to the same properties struct, except
+// the fields named in skip. The unsupported elements in src replace
+// those of the same name in dst.
+func overlayProperties(dst, src interface{}, skip ...string) {
+	d := reflect.ValueOf(dst).Elem()
+	v := reflect.ValueOf(src)
+	if v.IsNil() {
+		return
+	}
+	v = v.Elem()
+	for i := 1; i < d.NumField(); i++ {
+		field := d.Type().Field(i)
+		if !field.IsExported() || containsString(skip, field.Name) {
+			continue
+		}
+		x := v.Field(i)
+		if x.IsZero() {
+			continue
+		}
+		switch x.Kind() {
+		case reflect.Ptr:
+			n := reflect.New(x.Elem().Type())
+			n.Elem().Set(x.Elem())
+			d.Field(i).Set(n)
+		case reflect.Slice:
+			if others, ok := x.Interface().([]*RawXML); ok {
+				d.Field(i).Set(reflect.ValueOf(overlayOthers(d.Field(i).Interface().([]*RawXML), others)))
+				continue
+			}
+			d.Field(i).Set(x)
+		default:
+			d.Field(i).Set(x)
+		}
+	}
+}
+
+// overlayOthers replaces the elements in dst by those of the same name
+// in src and appends the rest
+func overlayOthers(dst, src []*RawXML) []*RawXML {
+	out := make([]*RawXML, 0, len(dst)+len(src))
+	for _, o := range dst {
+		replaced := false
+		for _, n := range src {
+			if n.Name() == o.Name() {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, o)
+		}
+	}
+	return append(out, src...)
+}
+
+func containsString(lst []string, s string) bool {
+	for _, x := range lst {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pPrNotInherited are the fields of ParagraphProperties that are not
+// a part of the effective paragraph formatting
+var pPrNotInherited = []string{"RunProperties", "SectPr", "Change"}
+
+// ResolveParagraphProperties returns the effective properties of p after
+// applying the document defaults, the style chain of p and the direct
+// formatting of p in order. An element in a later level replaces
+// the whole element of the same kind in an earlier one. The run
+// properties of the paragraph mark, the section properties and the
+// tracked change are not included.
+func (s *Styles) ResolveParagraphProperties(p *Paragraph) *ParagraphProperties {
+	pp := &ParagraphProperties{}
+	if s.DocDefaults != nil && s.DocDefaults.PPrDefault != nil {
+		overlayProperties(pp, s.DocDefaults.PPrDefault.ParagraphProperties, pPrNotInherited...)
+	}
+	for _, sd := range s.paragraphStyleChain(p) {
+		overlayProperties(pp, sd.ParagraphProperties, pPrNotInherited...)
+	}
+	if p != nil {
+		overlayProperties(pp, p.Properties, pPrNotInherited...)
+	}
+	return pp
+}
+
+// ResolveRunProperties returns the effective properties of r in p after
+// applying the document defaults, the paragraph style chain of p, the
+// character style chain of r and the direct formatting of r in order.
+// An element in a later level replaces the whole element of the same
+// kind in an earlier one, and toggle properties like Bold are not toggled.
+func (s *Styles) ResolveRunProperties(p *Paragraph, r *Run) *RunProperties {
+	rp := &RunProperties{}
+	if s.DocDefaults != nil && s.DocDefaults.RPrDefault != nil {
+		overlayProperties(rp, s.DocDefaults.RPrDefault.RunProperties)
+	}
+	for _, sd := range s.paragraphStyleChain(p) {
+		overlayProperties(rp, sd.RunProperties)
+	}
+	if r != nil && r.RunProperties != nil && r.RunProperties.RunStyle != nil {
+		for _, sd := range s.chain(r.RunProperties.RunStyle.Val) {
+			overlayProperties(rp, sd.RunProperties)
+		}
+	}
+	if r != nil {
+		overlayProperties(rp, r.RunProperties)
+	}
+	rp.RunStyle = nil
+	rp.Style = nil
+	return rp
+}
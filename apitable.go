@@ -20,10 +20,30 @@
 
 package docx
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	// ErrCellOutOfRange is returned if a cell is not in the table
+	ErrCellOutOfRange = errors.New("cell out of range")
+	// ErrMergeCutsCells is returned if the range to merge cuts merged cells
+	ErrMergeCutsCells = errors.New("merge range cuts merged cells")
+)
+
 // AddTable add a new table to body by col*row
 //
 // unit: twips (1/20 point)
 func (f *Docx) AddTable(row int, col int) *Table {
+	tbl := f.newTable(row, col)
+	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
+	return tbl
+}
+
+// newTable makes a new table by col*row without adding it
+func (f *Docx) newTable(row int, col int) *Table {
 	trs := make([]*WTableRow, row)
 	for i := 0; i < row; i++ {
 		cells := make([]*WTableCell, col)
@@ -57,15 +77,62 @@ func (f *Docx) AddTable(row int, col int) *Table {
 		},
 		TableGrid: &WTableGrid{},
 		TableRows: trs,
+		file:      f,
 	}
-	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
 	return tbl
 }
 
+// newRowLike makes a new row with the cell widths and spans of tr,
+// or of the table grid if tr is nil, without adding it
+func (t *Table) newRowLike(tr *WTableRow) *WTableRow {
+	var cells []*WTableCell
+	if tr != nil {
+		cells = make([]*WTableCell, len(tr.TableCells))
+		for i, c := range tr.TableCells {
+			w := &WTableCellWidth{Type: "auto"}
+			if c.TableCellProperties != nil && c.TableCellProperties.TableCellWidth != nil {
+				nw := *c.TableCellProperties.TableCellWidth
+				w = &nw
+			}
+			cells[i] = &WTableCell{
+				TableCellProperties: &WTableCellProperties{TableCellWidth: w},
+				file:                t.file,
+			}
+			if span := c.gridSpan(); span > 1 {
+				cells[i].TableCellProperties.GridSpan = &WGridSpan{Val: span}
+			}
+		}
+	} else if t.TableGrid != nil {
+		cells = make([]*WTableCell, len(t.TableGrid.GridCols))
+		for i, g := range t.TableGrid.GridCols {
+			w := &WTableCellWidth{Type: "auto"}
+			if g != nil && g.W > 0 {
+				w = &WTableCellWidth{W: g.W, Type: "dxa"}
+			}
+			cells[i] = &WTableCell{
+				TableCellProperties: &WTableCellProperties{TableCellWidth: w},
+				file:                t.file,
+			}
+		}
+	}
+	return &WTableRow{
+		TableRowProperties: &WTableRowProperties{},
+		TableCells:         cells,
+		file:               t.file,
+	}
+}
+
 // AddTableTwips add a new table to body by height and width
 //
 // unit: twips (1/20 point)
 func (f *Docx) AddTableTwips(rowHeights []int64, colWidths []int64) *Table {
+	tbl := f.newTableTwips(rowHeights, colWidths)
+	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
+	return tbl
+}
+
+// newTableTwips makes a new table by height and width without adding it
+func (f *Docx) newTableTwips(rowHeights []int64, colWidths []int64) *Table {
 	grids := make([]*WGridCol, len(colWidths))
 	trs := make([]*WTableRow, len(rowHeights))
 	for i, w := range colWidths {
@@ -114,11 +181,87 @@ func (f *Docx) AddTableTwips(rowHeights []int64, colWidths []int64) *Table {
 			GridCols: grids,
 		},
 		TableRows: trs,
+		file:      f,
 	}
-	f.Document.Body.Items = append(f.Document.Body.Items, tbl)
 	return tbl
 }
 
+// Style applies the table style of styleId id with the parts enabled by look.
+// The borders set on the table itself take precedence over the style,
+// so they are removed to show the borders of the style.
+func (t *Table) Style(id string, look TableLook) *Table {
+	if t.TableProperties == nil {
+		t.TableProperties = &WTableProperties{}
+	}
+	t.TableProperties.Style = &WTableStyle{Val: id}
+	t.TableProperties.TableBorders = nil
+	t.TableProperties.Look = look.tableLook()
+	return t
+}
+
+// the bits of the legacy w:val of WTableLook
+//
+//nolint:revive,stylecheck
+const (
+	TABLE_LOOK_FIRST_ROW = 0x0020
+	TABLE_LOOK_LAST_ROW  = 0x0040
+	TABLE_LOOK_FIRST_COL = 0x0080
+	TABLE_LOOK_LAST_COL  = 0x0100
+	TABLE_LOOK_NO_HBAND  = 0x0200
+	TABLE_LOOK_NO_VBAND  = 0x0400
+)
+
+// tableLook makes the WTableLook with both the attributes and the legacy w:val
+func (l TableLook) tableLook() *WTableLook {
+	tl := &WTableLook{}
+	val := 0
+	for _, x := range []struct {
+		on  bool
+		att *int
+		bit int
+	}{
+		{l.FirstRow, &tl.FirstRow, TABLE_LOOK_FIRST_ROW},
+		{l.LastRow, &tl.LastRow, TABLE_LOOK_LAST_ROW},
+		{l.FirstCol, &tl.FirstCol, TABLE_LOOK_FIRST_COL},
+		{l.LastCol, &tl.LastCol, TABLE_LOOK_LAST_COL},
+		{l.NoHBand, &tl.NoHBand, TABLE_LOOK_NO_HBAND},
+		{l.NoVBand, &tl.NoVBand, TABLE_LOOK_NO_VBAND},
+	} {
+		if x.on {
+			*x.att = 1
+			val |= x.bit
+		}
+	}
+	tl.Val = fmt.Sprintf("%04X", val)
+	return tl
+}
+
+// Flags returns the typed flags of the look. The legacy w:val is used
+// if none of the attributes is set, as written by older versions of Word.
+func (l *WTableLook) Flags() TableLook {
+	if l.FirstRow|l.LastRow|l.FirstCol|l.LastCol|l.NoHBand|l.NoVBand == 0 {
+		val, err := strconv.ParseUint(l.Val, 16, 16)
+		if err == nil && val != 0 {
+			return TableLook{
+				FirstRow: val&TABLE_LOOK_FIRST_ROW != 0,
+				LastRow:  val&TABLE_LOOK_LAST_ROW != 0,
+				FirstCol: val&TABLE_LOOK_FIRST_COL != 0,
+				LastCol:  val&TABLE_LOOK_LAST_COL != 0,
+				NoHBand:  val&TABLE_LOOK_NO_HBAND != 0,
+				NoVBand:  val&TABLE_LOOK_NO_VBAND != 0,
+			}
+		}
+	}
+	return TableLook{
+		FirstRow: l.FirstRow != 0,
+		LastRow:  l.LastRow != 0,
+		FirstCol: l.FirstCol != 0,
+		LastCol:  l.LastCol != 0,
+		NoHBand:  l.NoHBand != 0,
+		NoVBand:  l.NoVBand != 0,
+	}
+}
+
 // Justification allows to set table's horizonal alignment
 //
 //	w:jc 属性的取值可以是以下之一：
@@ -162,3 +305,557 @@ func (c *WTableCell) Shade(val, color, fill string) *WTableCell {
 	}
 	return c
 }
+
+// gridSpan is the number of grid columns taken by c
+func (c *WTableCell) gridSpan() int {
+	if c.TableCellProperties != nil && c.TableCellProperties.GridSpan != nil && c.TableCellProperties.GridSpan.Val > 1 {
+		return c.TableCellProperties.GridSpan.Val
+	}
+	return 1
+}
+
+// Paragraphs returns the paragraphs directly in c without those in the nested tables
+func (c *WTableCell) Paragraphs() []*Paragraph {
+	ps := make([]*Paragraph, 0, len(c.Items))
+	for _, it := range c.Items {
+		if p, ok := it.(*Paragraph); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// AddTable adds a new table nested in the cell by col*row.
+// A paragraph should be added after it as Word requires
+// a cell to end with a paragraph.
+func (c *WTableCell) AddTable(row int, col int) *Table {
+	tbl := c.file.newTable(row, col)
+	c.Items = append(c.Items, tbl)
+	return tbl
+}
+
+// vMergeContinued reports whether c is merged into the cell above
+func (c *WTableCell) vMergeContinued() bool {
+	return c.TableCellProperties != nil && c.TableCellProperties.VMerge != nil && c.TableCellProperties.VMerge.Val != "restart"
+}
+
+// properties returns the properties of c, which will be added if not exist
+func (c *WTableCell) properties() *WTableCellProperties {
+	if c.TableCellProperties == nil {
+		c.TableCellProperties = &WTableCellProperties{}
+	}
+	return c.TableCellProperties
+}
+
+// cellAt finds the index of the cell covering col of the table grid
+// and the column it starts at, or -1 if not found
+func (w *WTableRow) cellAt(col int) (int, int) {
+	start := 0
+	for i, c := range w.TableCells {
+		span := c.gridSpan()
+		if col >= start && col < start+span {
+			return i, start
+		}
+		start += span
+	}
+	return -1, -1
+}
+
+// gridColumns is the number of columns in the table grid
+func (t *Table) gridColumns() int {
+	n := 0
+	if t.TableGrid != nil {
+		n = len(t.TableGrid.GridCols)
+	}
+	for _, tr := range t.TableRows {
+		cols := 0
+		for _, c := range tr.TableCells {
+			cols += c.gridSpan()
+		}
+		if cols > n {
+			n = cols
+		}
+	}
+	return n
+}
+
+// Cell gets the cell (or nil on notfound) at row and col of the table grid.
+// A position in a merged cell returns the merged one.
+func (t *Table) Cell(row, col int) *WTableCell {
+	for ; row >= 0 && row < len(t.TableRows); row-- {
+		tr := t.TableRows[row]
+		i, start := tr.cellAt(col)
+		if i < 0 {
+			return nil
+		}
+		if !tr.TableCells[i].vMergeContinued() {
+			return tr.TableCells[i]
+		}
+		col = start
+	}
+	return nil
+}
+
+// hasContent reports whether c has a table or a paragraph with children
+func (c *WTableCell) hasContent() bool {
+	for _, it := range c.Items {
+		switch o := it.(type) {
+		case *Paragraph:
+			if len(o.Children) > 0 {
+				return true
+			}
+		case *Table:
+			return true
+		}
+	}
+	return false
+}
+
+// MergeCells merges the cells from (r1, c1) to (r2, c2) of the table grid
+// into the top left one by GridSpan and VMerge. The paragraphs of the other
+// cells having content are moved to the end of the merged one like Word does.
+// The range must not cut any merged cells.
+func (t *Table) MergeCells(r1, c1, r2, c2 int) error {
+	if r1 > r2 {
+		r1, r2 = r2, r1
+	}
+	if c1 > c2 {
+		c1, c2 = c2, c1
+	}
+	if r1 < 0 || c1 < 0 || r2 >= len(t.TableRows) {
+		return ErrCellOutOfRange
+	}
+	// the first and the last index of the cells in each row
+	idx := make([][2]int, r2-r1+1)
+	for r := r1; r <= r2; r++ {
+		tr := t.TableRows[r]
+		i1, s1 := tr.cellAt(c1)
+		i2, s2 := tr.cellAt(c2)
+		if i1 < 0 || i2 < 0 {
+			return ErrCellOutOfRange
+		}
+		if s1 != c1 || s2+tr.TableCells[i2].gridSpan()-1 != c2 {
+			return ErrMergeCutsCells
+		}
+		if r == r1 {
+			for _, c := range tr.TableCells[i1 : i2+1] {
+				if c.vMergeContinued() {
+					return ErrMergeCutsCells
+				}
+			}
+		}
+		idx[r-r1] = [2]int{i1, i2}
+	}
+	if r2+1 < len(t.TableRows) {
+		tr := t.TableRows[r2+1]
+		for col := c1; col <= c2; {
+			i, start := tr.cellAt(col)
+			if i < 0 {
+				break
+			}
+			if tr.TableCells[i].vMergeContinued() {
+				return ErrMergeCutsCells
+			}
+			col = start + tr.TableCells[i].gridSpan()
+		}
+	}
+
+	top := t.TableRows[r1].TableCells[idx[0][0]]
+	moved := make([]interface{}, 0, 8)
+	for r := r1; r <= r2; r++ {
+		tr := t.TableRows[r]
+		i1, i2 := idx[r-r1][0], idx[r-r1][1]
+		first := tr.TableCells[i1]
+		var width *WTableCellWidth
+		for _, c := range tr.TableCells[i1 : i2+1] {
+			if c != top && c.hasContent() {
+				moved = append(moved, c.Items...)
+			}
+			if c.TableCellProperties == nil || c.TableCellProperties.TableCellWidth == nil {
+				continue
+			}
+			w := c.TableCellProperties.TableCellWidth
+			switch {
+			case width == nil:
+				nw := *w
+				width = &nw
+			case width.Type == w.Type:
+				width.W += w.W
+			}
+		}
+		tr.TableCells = append(tr.TableCells[:i1+1], tr.TableCells[i2+1:]...)
+		pr := first.properties()
+		if width != nil {
+			pr.TableCellWidth = width
+		}
+		pr.GridSpan = nil
+		if c2 > c1 {
+			pr.GridSpan = &WGridSpan{Val: c2 - c1 + 1}
+		}
+		pr.VMerge = nil
+		if r2 > r1 {
+			pr.VMerge = &WvMerge{}
+			if r == r1 {
+				pr.VMerge.Val = "restart"
+			}
+		}
+		if first != top {
+			first.Items = nil
+			first.AddParagraph()
+		}
+	}
+	if len(moved) > 0 {
+		if !top.hasContent() {
+			top.Items = nil
+		}
+		top.Items = append(top.Items, moved...)
+	}
+	return nil
+}
+
+// Unmerge splits the merged cell at (row, col) of the table grid back into
+// one cell for each position in the grid, the new cells are empty.
+func (t *Table) Unmerge(row, col int) error {
+	if row < 0 || row >= len(t.TableRows) {
+		return ErrCellOutOfRange
+	}
+	i, start := t.TableRows[row].cellAt(col)
+	if i < 0 {
+		return ErrCellOutOfRange
+	}
+	for row > 0 && t.TableRows[row].TableCells[i].vMergeContinued() {
+		j, s := t.TableRows[row-1].cellAt(start)
+		if j < 0 || s != start {
+			break
+		}
+		row, i = row-1, j
+	}
+	for r := row; r < len(t.TableRows); r++ {
+		tr := t.TableRows[r]
+		if r > row {
+			j, s := tr.cellAt(start)
+			if j < 0 || s != start || !tr.TableCells[j].vMergeContinued() {
+				break
+			}
+			i = j
+		}
+		c := tr.TableCells[i]
+		span := c.gridSpan()
+		pr := c.properties()
+		pr.VMerge = nil
+		if span == 1 {
+			continue
+		}
+		pr.GridSpan = nil
+		cells := make([]*WTableCell, span)
+		cells[0] = c
+		for k := 0; k < span; k++ {
+			w := t.splitWidth(pr.TableCellWidth, start+k, span)
+			if k == 0 {
+				if w != nil {
+					pr.TableCellWidth = w
+				}
+				continue
+			}
+			cells[k] = &WTableCell{
+				TableCellProperties: &WTableCellProperties{TableCellWidth: w},
+				file:                t.file,
+			}
+			cells[k].AddParagraph()
+		}
+		tr.TableCells = append(tr.TableCells[:i], append(cells, tr.TableCells[i+1:]...)...)
+	}
+	return nil
+}
+
+// splitWidth returns the width of col in a cell of width w spanning span columns
+func (t *Table) splitWidth(w *WTableCellWidth, col, span int) *WTableCellWidth {
+	if t.TableGrid != nil && col < len(t.TableGrid.GridCols) && t.TableGrid.GridCols[col] != nil && t.TableGrid.GridCols[col].W > 0 {
+		return &WTableCellWidth{W: t.TableGrid.GridCols[col].W, Type: "dxa"}
+	}
+	if w == nil {
+		return &WTableCellWidth{Type: "auto"}
+	}
+	return &WTableCellWidth{W: w.W / int64(span), Type: w.Type}
+}
+
+// alignedCell gets the cell (or nil on notfound) starting at col
+// of the table grid and spanning span columns
+func (w *WTableRow) alignedCell(col, span int) *WTableCell {
+	i, start := w.cellAt(col)
+	if i < 0 || start != col || w.TableCells[i].gridSpan() != span {
+		return nil
+	}
+	return w.TableCells[i]
+}
+
+// fixVMerge makes the vertical merges consistent after the rows
+// or the columns have been changed: a continued cell without a merged
+// cell above starts the merge, and a merge of only one cell is dropped.
+func (t *Table) fixVMerge() {
+	for r, tr := range t.TableRows {
+		start := 0
+		for _, c := range tr.TableCells {
+			span := c.gridSpan()
+			if c.vMergeContinued() {
+				var above *WTableCell
+				if r > 0 {
+					above = t.TableRows[r-1].alignedCell(start, span)
+				}
+				if above == nil || above.TableCellProperties == nil || above.TableCellProperties.VMerge == nil {
+					c.TableCellProperties.VMerge = &WvMerge{Val: "restart"}
+				}
+			}
+			start += span
+		}
+	}
+	for r, tr := range t.TableRows {
+		start := 0
+		for _, c := range tr.TableCells {
+			span := c.gridSpan()
+			if c.TableCellProperties != nil && c.TableCellProperties.VMerge != nil && !c.vMergeContinued() {
+				var below *WTableCell
+				if r+1 < len(t.TableRows) {
+					below = t.TableRows[r+1].alignedCell(start, span)
+				}
+				if below == nil || !below.vMergeContinued() {
+					c.TableCellProperties.VMerge = nil
+				}
+			}
+			start += span
+		}
+	}
+}
+
+// InsertRow inserts a new empty row at index at (len(TableRows) to append)
+// with the formatting of the row at index like, and returns it.
+// The paragraph properties of the first paragraph in each cell are kept.
+// A row inserted into vertically merged cells extends the merge.
+func (t *Table) InsertRow(at, like int) (*WTableRow, error) {
+	tr, err := t.cloneRow(at, like)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range tr.TableCells {
+		var pr *ParagraphProperties
+		if ps := c.Paragraphs(); len(ps) > 0 {
+			pr = ps[0].Properties
+		}
+		c.Items = nil
+		c.AddParagraph().Properties = pr
+	}
+	t.insertRow(at, tr)
+	return tr, nil
+}
+
+// CloneRow inserts a deep copy of the row at index idx with all its content
+// at index at (len(TableRows) to append), and returns it.
+func (t *Table) CloneRow(idx, at int) (*WTableRow, error) {
+	tr, err := t.cloneRow(at, idx)
+	if err != nil {
+		return nil, err
+	}
+	t.insertRow(at, tr)
+	return tr, nil
+}
+
+// cloneRow makes a deep copy of the row at index idx
+// without vertical merges to be inserted at index at
+func (t *Table) cloneRow(at, idx int) (*WTableRow, error) {
+	if idx < 0 || idx >= len(t.TableRows) || at < 0 || at > len(t.TableRows) {
+		return nil, ErrCellOutOfRange
+	}
+	rows, err := t.file.cloneRows(t.TableRows[idx:idx+1], true)
+	if err != nil {
+		return nil, err
+	}
+	tr := rows[0]
+	for _, c := range tr.TableCells {
+		if c.TableCellProperties != nil {
+			c.TableCellProperties.VMerge = nil
+		}
+		walkParagraphs(c.Items, func(p *Paragraph) bool {
+			p.ParaID = ""
+			return true
+		})
+	}
+	return tr, nil
+}
+
+// insertRow inserts tr at index at, continuing the merged cells
+// that go across at
+func (t *Table) insertRow(at int, tr *WTableRow) {
+	if at > 0 && at < len(t.TableRows) {
+		above, below := t.TableRows[at-1], t.TableRows[at]
+		start := 0
+		for _, c := range tr.TableCells {
+			span := c.gridSpan()
+			a, b := above.alignedCell(start, span), below.alignedCell(start, span)
+			if a != nil && a.TableCellProperties != nil && a.TableCellProperties.VMerge != nil && b != nil && b.vMergeContinued() {
+				c.properties().VMerge = &WvMerge{}
+				c.Items = nil
+				c.AddParagraph()
+			}
+			start += span
+		}
+	}
+	t.TableRows = append(t.TableRows, nil)
+	copy(t.TableRows[at+1:], t.TableRows[at:])
+	t.TableRows[at] = tr
+}
+
+// DeleteRow removes the row at index idx. The content of a merged cell
+// starting in the row is moved to the cell below that continues the merge.
+func (t *Table) DeleteRow(idx int) error {
+	if idx < 0 || idx >= len(t.TableRows) {
+		return ErrCellOutOfRange
+	}
+	tr := t.TableRows[idx]
+	if idx+1 < len(t.TableRows) {
+		start := 0
+		for _, c := range tr.TableCells {
+			span := c.gridSpan()
+			if !c.vMergeContinued() && c.hasContent() {
+				below := t.TableRows[idx+1].alignedCell(start, span)
+				if below != nil && below.vMergeContinued() {
+					below.Items = c.Items
+				}
+			}
+			start += span
+		}
+	}
+	t.TableRows = append(t.TableRows[:idx], t.TableRows[idx+1:]...)
+	t.fixVMerge()
+	return nil
+}
+
+// InsertColumn inserts a new empty column at col of the table grid
+// (the number of columns to append) with the formatting of the cells
+// on its left (or right if col is 0). The cells spanning across col
+// are widened instead.
+//
+// unit: twips (1/20 point), 0 for auto width, in which case the grid
+// column takes the width of its neighbour
+func (t *Table) InsertColumn(col int, width int64) error {
+	cols := t.gridColumns()
+	if col < 0 || col > cols {
+		return ErrCellOutOfRange
+	}
+	for _, tr := range t.TableRows {
+		i, start := tr.cellAt(col)
+		if i < 0 {
+			i = len(tr.TableCells)
+		}
+		if i < len(tr.TableCells) && start < col {
+			pr := tr.TableCells[i].properties()
+			pr.GridSpan = &WGridSpan{Val: tr.TableCells[i].gridSpan() + 1}
+			if w := pr.TableCellWidth; w != nil && w.Type == "dxa" {
+				w.W += width
+			}
+			continue
+		}
+		like := i - 1
+		if like < 0 {
+			like = i
+		}
+		c := &WTableCell{file: t.file}
+		if like < len(tr.TableCells) {
+			rows, err := t.file.cloneRows([]*WTableRow{{TableCells: tr.TableCells[like : like+1]}}, false)
+			if err != nil {
+				return err
+			}
+			c.TableCellProperties = rows[0].TableCells[0].TableCellProperties
+		}
+		pr := c.properties()
+		pr.VMerge = nil
+		pr.GridSpan = nil
+		pr.TableCellWidth = &WTableCellWidth{Type: "auto"}
+		if width > 0 {
+			pr.TableCellWidth = &WTableCellWidth{W: width, Type: "dxa"}
+		}
+		c.AddParagraph()
+		tr.TableCells = append(tr.TableCells, nil)
+		copy(tr.TableCells[i+1:], tr.TableCells[i:])
+		tr.TableCells[i] = c
+	}
+	if t.TableGrid != nil && len(t.TableGrid.GridCols) == cols {
+		g := &WGridCol{W: width}
+		if width <= 0 {
+			g.W = t.TableGrid.autoWidth(col)
+		}
+		t.TableGrid.GridCols = append(t.TableGrid.GridCols, nil)
+		copy(t.TableGrid.GridCols[col+1:], t.TableGrid.GridCols[col:])
+		t.TableGrid.GridCols[col] = g
+	}
+	return nil
+}
+
+// autoWidth returns the width of a new grid column at col,
+// that is the width of the column on its left (or right if col is 0)
+// or the average width of the grid
+func (g *WTableGrid) autoWidth(col int) int64 {
+	like := col - 1
+	if like < 0 {
+		like = col
+	}
+	if like < len(g.GridCols) && g.GridCols[like] != nil && g.GridCols[like].W > 0 {
+		return g.GridCols[like].W
+	}
+	sum, n := int64(0), int64(0)
+	for _, c := range g.GridCols {
+		if c != nil && c.W > 0 {
+			sum += c.W
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / n
+}
+
+// DeleteColumn removes the column at col of the table grid.
+// The cells spanning across col are narrowed instead,
+// and the rows left without cells are removed.
+func (t *Table) DeleteColumn(col int) error {
+	cols := t.gridColumns()
+	if col < 0 || col >= cols {
+		return ErrCellOutOfRange
+	}
+	var gw int64
+	if t.TableGrid != nil && col < len(t.TableGrid.GridCols) && t.TableGrid.GridCols[col] != nil {
+		gw = t.TableGrid.GridCols[col].W
+	}
+	rows := t.TableRows[:0]
+	for _, tr := range t.TableRows {
+		i, _ := tr.cellAt(col)
+		if i >= 0 {
+			c := tr.TableCells[i]
+			if span := c.gridSpan(); span > 1 {
+				pr := c.properties()
+				if w := pr.TableCellWidth; w != nil && w.Type == "dxa" {
+					if gw > 0 {
+						w.W -= gw
+					} else {
+						w.W -= w.W / int64(span)
+					}
+				}
+				pr.GridSpan = nil
+				if span > 2 {
+					pr.GridSpan = &WGridSpan{Val: span - 1}
+				}
+			} else {
+				tr.TableCells = append(tr.TableCells[:i], tr.TableCells[i+1:]...)
+			}
+		}
+		if len(tr.TableCells) > 0 {
+			rows = append(rows, tr)
+		}
+	}
+	t.TableRows = rows
+	if t.TableGrid != nil && col < len(t.TableGrid.GridCols) && len(t.TableGrid.GridCols) == cols {
+		t.TableGrid.GridCols = append(t.TableGrid.GridCols[:col], t.TableGrid.GridCols[col+1:]...)
+	}
+	t.fixVMerge()
+	return nil
+}
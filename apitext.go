@@ -65,3 +65,25 @@ func (p *Paragraph) AddText(text string) *Run {
 	p.Children = append(p.Children, run)
 	return run
 }
+
+// paragraphText returns the text shown in the children of a paragraph,
+// without the markdown of links like Paragraph.String
+func paragraphText(children []interface{}) string {
+	sb := strings.Builder{}
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			sb.WriteString(runText(o))
+		case *Hyperlink:
+			sb.WriteString(o.Run.InstrText)
+			sb.WriteString(runText(&o.Run))
+		case *SimpleField:
+			sb.WriteString(paragraphText(o.Children))
+		case *Revision:
+			if o.IsInsertion() {
+				sb.WriteString(paragraphText(o.Children))
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,243 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrTOCNotFound the paragraphs of the table of contents are not in body
+var ErrTOCNotFound = errors.New("table of contents not found in body")
+
+// TableOfContents is a TOC field in the document body whose
+// cached result lists the headings with links to them
+type TableOfContents struct {
+	Levels int // Levels are the heading levels from 1 to show
+
+	paras []*Paragraph // paras are the paragraphs of the field in body
+	file  *Docx
+}
+
+// HeadingLevel returns the level (1-9) of p by its outline level or
+// heading style, or 0 if p is not a heading
+func (f *Docx) HeadingLevel(p *Paragraph) int {
+	if p.Properties != nil && p.Properties.OutlineLvl != nil {
+		return outlineToHeading(p.Properties.OutlineLvl.Val)
+	}
+	id := ""
+	if p.Properties != nil && p.Properties.Style != nil {
+		id = p.Properties.Style.Val
+	}
+	s, err := f.lookupStyles()
+	if err == nil {
+		chain := s.paragraphStyleChain(p)
+		for i := len(chain) - 1; i >= 0; i-- {
+			sd := chain[i]
+			if sd.ParagraphProperties != nil && sd.ParagraphProperties.OutlineLvl != nil {
+				return outlineToHeading(sd.ParagraphProperties.OutlineLvl.Val)
+			}
+			if sd.Name != nil {
+				if lv := headingNumber(strings.ToLower(sd.Name.Val), "heading "); lv > 0 {
+					return lv
+				}
+			}
+		}
+	}
+	return headingNumber(strings.ToLower(id), "heading")
+}
+
+// outlineToHeading converts the 0-based outline level to heading level
+func outlineToHeading(lvl int) int {
+	if lvl < 0 || lvl > 8 {
+		return 0 // body text
+	}
+	return lvl + 1
+}
+
+// headingNumber parses s like heading 1 to 1, or returns 0
+func headingNumber(s, prefix string) int {
+	if !strings.HasPrefix(s, prefix) || len(s) != len(prefix)+1 {
+		return 0
+	}
+	lv := int(s[len(prefix)] - '0')
+	if lv < 1 || lv > 9 {
+		return 0
+	}
+	return lv
+}
+
+// headingStyle returns the id of the style of heading level,
+// which will be added if not exist
+func (s *Styles) headingStyle(level int) string {
+	n := strconv.Itoa(level)
+	if sd := s.StyleByName("heading " + n); sd != nil {
+		return sd.StyleID
+	}
+	sd := s.AddStyle(STYLE_TYPE_PARAGRAPH, "Heading"+n, "heading "+n).WithQFormat().WithUIPriority(9).Bold()
+	sd.CustomStyle = ""
+	if d := s.Default(STYLE_TYPE_PARAGRAPH); d != nil {
+		sd.WithBasedOn(d.StyleID)
+	}
+	size := 36 - 4*level // in half points
+	if size < 22 {
+		size = 22
+	}
+	sd.Size(strconv.Itoa(size)).paragraphProperties().OutlineLvl = &OutlineLvl{Val: level - 1}
+	if level < 4 {
+		after := 120
+		sd.paragraphProperties().Spacing = &Spacing{Before: 240, After: &after}
+	}
+	return sd.StyleID
+}
+
+// tocStyle returns the id of the style of TOC entries of level,
+// which will be added if not exist
+func (s *Styles) tocStyle(level int) string {
+	n := strconv.Itoa(level)
+	if sd := s.StyleByName("toc " + n); sd != nil {
+		return sd.StyleID
+	}
+	sd := s.AddStyle(STYLE_TYPE_PARAGRAPH, "TOC"+n, "toc "+n).WithUIPriority(39)
+	sd.CustomStyle = ""
+	if d := s.Default(STYLE_TYPE_PARAGRAPH); d != nil {
+		sd.WithBasedOn(d.StyleID)
+	}
+	if level > 1 {
+		sd.paragraphProperties().Ind = &Ind{Left: 420 * (level - 1)}
+	}
+	return sd.StyleID
+}
+
+// AddHeading adds a paragraph of text in the heading style of level (1-9),
+// which will be added into styles if not exist
+func (f *Docx) AddHeading(text string, level int) (*Paragraph, error) {
+	if level < 1 || level > 9 {
+		return nil, errors.New("invalid heading level " + strconv.Itoa(level))
+	}
+	s, err := f.Styles()
+	if err != nil {
+		return nil, err
+	}
+	p := f.AddParagraph().Style(s.headingStyle(level))
+	p.AddText(text)
+	return p, nil
+}
+
+// AddTableOfContents adds a TOC field of the headings of levels from 1
+// with the entries of the current headings as its cached result.
+// Call Update after adding more headings. Word will refresh the
+// page numbers on opening.
+func (f *Docx) AddTableOfContents(levels int) (*TableOfContents, error) {
+	if levels < 1 || levels > 9 {
+		return nil, errors.New("invalid toc levels " + strconv.Itoa(levels))
+	}
+	toc := &TableOfContents{Levels: levels, file: f}
+	p := f.AddParagraph()
+	toc.paras = []*Paragraph{p}
+	return toc, toc.Update()
+}
+
+// Update renders the entries of the current headings in body again
+func (toc *TableOfContents) Update() error {
+	f := toc.file
+	items := f.Document.Body.Items
+	start := -1
+	for i, it := range items {
+		if it == toc.paras[0] {
+			start = i
+			break
+		}
+	}
+	if start < 0 || start+len(toc.paras) > len(items) {
+		return ErrTOCNotFound
+	}
+	s, err := f.Styles()
+	if err != nil {
+		return err
+	}
+	width := 8296 // A4 with default margins
+	if sect := f.lastSectPr(); sect != nil && sect.PgSz != nil && sect.PgMar != nil {
+		if w, err := GetInt(sect.PgSz.W.Value); err == nil && w > sect.PgMar.Left+sect.PgMar.Right {
+			width = w - sect.PgMar.Left - sect.PgMar.Right
+		}
+	}
+	paras := make([]*Paragraph, 0, 16)
+	for i, it := range items {
+		p, ok := it.(*Paragraph)
+		if !ok || (i >= start && i < start+len(toc.paras)) {
+			continue
+		}
+		lv := f.HeadingLevel(p)
+		if lv == 0 || lv > toc.Levels {
+			continue
+		}
+		anchor := ""
+		for _, c := range p.Children {
+			if b, ok := c.(*BookmarkStart); ok && strings.HasPrefix(b.Name, "_Toc") {
+				anchor = b.Name
+				break
+			}
+		}
+		if anchor == "" {
+			anchor = p.AddBookmark("_Toc" + strconv.Itoa(len(paras)+1)).Name
+		}
+		entry := &Paragraph{
+			Properties: &ParagraphProperties{
+				Style: &Style{Val: s.tocStyle(lv)},
+				Tabs:  &Tabs{Tabs: []*Tab{{Val: "right", Leader: "dot", Position: width}}},
+			},
+			Children: make([]interface{}, 0, 16),
+			file:     f,
+		}
+		entry.Children = append(entry.Children, &Hyperlink{
+			Anchor: anchor,
+			Run: Run{
+				Children: []interface{}{&Text{Text: paragraphText(p.Children), XMLSpace: "preserve"}},
+				file:     f,
+			},
+		})
+		entry.AddTab()
+		entry.AddField("PAGEREF "+anchor+` \h`, "")
+		paras = append(paras, entry)
+	}
+	if len(paras) == 0 {
+		p := &Paragraph{Children: make([]interface{}, 0, 8), file: f}
+		p.AddText("No table of contents entries found.")
+		paras = append(paras, p)
+	}
+	fd := (&Paragraph{file: f}).AddField(`TOC \o "1-`+strconv.Itoa(toc.Levels)+`" \h \z \u`, "")
+	fd.MarkDirty()
+	first, last := paras[0], paras[len(paras)-1]
+	first.Children = append([]interface{}{fd.Begin, fd.Instr[0], fd.Separate}, first.Children...)
+	last.Children = append(last.Children, fd.End)
+
+	nitems := make([]interface{}, 0, len(items)-len(toc.paras)+len(paras))
+	nitems = append(nitems, items[:start]...)
+	for _, p := range paras {
+		nitems = append(nitems, p)
+	}
+	nitems = append(nitems, items[start+len(toc.paras):]...)
+	f.Document.Body.Items = nitems
+	toc.paras = paras
+	return nil
+}
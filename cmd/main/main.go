@@ -26,11 +26,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 
-	"github.com/fumiama/go-docx"
+	"github.com/superDCF/go-docx"
 )
 
 func main() {
@@ -41,6 +42,7 @@ func main() {
 	splitre := flag.String("s", "", "split file into many docxs by matching regex")
 	droppp := flag.Bool("p", false, "drop all paragraph properties")
 	dupnum := flag.Uint("d", 0, "copy times of the file into dup_filename")
+	mdfile := flag.String("m", "", "export file as markdown with images in its _media folder")
 	flag.Parse()
 	var w *docx.Docx
 	if !*analyzeOnly {
@@ -192,7 +194,7 @@ func main() {
 			case *docx.Table: // printable
 				for _, tr := range o.TableRows {
 					for _, tc := range tr.TableCells {
-						for _, p := range tc.Paragraphs {
+						for _, p := range tc.Paragraphs() {
 							p.Properties = nil
 						}
 					}
@@ -217,12 +219,23 @@ func main() {
 		}
 	}
 	fmt.Println("Plain text:")
-	for _, it := range doc.Document.Body.Items {
-		switch o := it.(type) {
-		case *docx.Paragraph: // printable
-			fmt.Println(o.String())
-		case *docx.Table: // printable
-			fmt.Println(o.String())
+	fmt.Print(doc.PlainText(docx.PlainTextOptions{AltText: true}))
+	if *mdfile != "" {
+		f, err := os.Create(*mdfile)
+		if err != nil {
+			panic(err)
+		}
+		media := strings.TrimSuffix(filepath.Base(*mdfile), filepath.Ext(*mdfile)) + "_media"
+		err = doc.WriteMarkdown(f, docx.MarkdownOptions{
+			MediaDir:  filepath.Join(filepath.Dir(*mdfile), media),
+			MediaLink: media,
+		})
+		if err != nil {
+			panic(err)
+		}
+		err = f.Close()
+		if err != nil {
+			panic(err)
 		}
 	}
 	if *splitre != "" {
@@ -38,10 +38,37 @@ type Docx struct {
 
 	docRelation Relationships // docRelation is word/_rels/document.xml.rels
 
+	headers []*Header // headers are word/headerN.xml
+	footers []*Footer // footers are word/footerN.xml
+
+	// partRels are the relationships of the headers, footers, notes and comments
+	// like word/_rels/header1.xml.rels by the part names, with the IDs unique in the file
+	partRels map[string]*Relationships
+
+	footnotes *Footnotes // footnotes is word/footnotes.xml, nil if not exist
+	endnotes  *Endnotes  // endnotes is word/endnotes.xml, nil if not exist
+	comments  *Comments  // comments is word/comments.xml, nil if not exist
+
+	copiedComments map[*Comment]*Comment  // copiedComments maps the comments copied from other files
+	copiedParts    map[interface{}]string // copiedParts maps the headers and footers copied from other files to their rIds
+
+	bookmarks *bookmarkTable // bookmarks are the names and ids used, nil if not loaded
+
+	styles     *Styles    // styles is word/styles.xml, nil if not loaded
+	readStyles *Styles    // readStyles is word/styles.xml loaded for reading only, nil if not loaded
+	numbering  *Numbering // numbering is word/numbering.xml, nil if not loaded
+
+	contentTypes *ContentTypes // contentTypes is [Content_Types].xml, nil if not loaded
+
+	coreProps   *CoreProperties   // coreProps is docProps/core.xml, nil if not loaded
+	appProps    *AppProperties    // appProps is docProps/app.xml, nil if not loaded
+	customProps *CustomProperties // customProps is docProps/custom.xml, nil if not loaded
+
 	media        []Media
 	mediaNameIdx map[string]int
 
 	rID       uintptr
+	revID     uintptr // revID is the next unused id of tracked changes
 	imageID   uintptr
 	docID     uintptr
 	slowIDs   map[string]uintptr
@@ -113,6 +140,7 @@ func LoadBodyItems(items []interface{}, media []Media) *Docx {
 			XMLWPS: XMLNS_WPS,
 			XMLWPC: XMLNS_WPC,
 			XMLWPG: XMLNS_WPG,
+			XMLW14: XMLNS_W14,
 			Body:   Body{Items: items},
 		},
 		docRelation: Relationships{
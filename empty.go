@@ -36,6 +36,7 @@ func newEmptyFile() *Docx {
 			XMLWPS: XMLNS_WPS,
 			XMLWPC: XMLNS_WPC,
 			XMLWPG: XMLNS_WPG,
+			XMLW14: XMLNS_W14,
 			// XMLMC:  XMLNS_MC,
 			// XMLO:   XMLNS_O,
 			// XMLV:   XMLNS_V,
@@ -2,7 +2,4 @@ module github.com/superDCF/go-docx
 
 go 1.20
 
-require (
-	github.com/fumiama/go-docx v0.0.0-20240428133836-8f66e2b8667f
-	github.com/fumiama/imgsz v0.0.2
-)
+require github.com/fumiama/imgsz v0.0.2
@@ -0,0 +1,600 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/base64"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// htmlColorRegex matches the colors that are safe to be put into css
+var htmlColorRegex = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+
+// cssColor returns the css color of the hex val of Word,
+// or "" if val is auto or not a valid color
+func cssColor(val string) string {
+	if !htmlColorRegex.MatchString(val) {
+		return ""
+	}
+	return "#" + val
+}
+
+// cssString quotes s as a css string, escaping all characters
+// but letters, digits and spaces
+func cssString(s string) string {
+	sb := strings.Builder{}
+	sb.WriteByte('"')
+	for _, r := range s {
+		if r == ' ' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteString(`\` + strconv.FormatInt(int64(r), 16) + " ")
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// safeHref returns href if it is an anchor or a http, https or mailto link,
+// or "" to drop the link
+func safeHref(href string) string {
+	if strings.HasPrefix(href, "#") {
+		return href
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return href
+	}
+	return ""
+}
+
+// htmlHighlights are the css colors of the highlights
+var htmlHighlights = map[string]string{
+	"black":       "black",
+	"blue":        "blue",
+	"cyan":        "cyan",
+	"green":       "lime",
+	"magenta":     "magenta",
+	"red":         "red",
+	"yellow":      "yellow",
+	"white":       "white",
+	"darkBlue":    "navy",
+	"darkCyan":    "teal",
+	"darkGreen":   "green",
+	"darkMagenta": "purple",
+	"darkRed":     "maroon",
+	"darkYellow":  "olive",
+	"darkGray":    "gray",
+	"lightGray":   "silver",
+}
+
+// htmlUnderlines are the css styles of the underlines
+var htmlUnderlines = map[string]string{
+	"double":      " double",
+	"dotted":      " dotted",
+	"dottedHeavy": " dotted",
+	"dash":        " dashed",
+	"dashLong":    " dashed",
+	"dashedHeavy": " dashed",
+	"wave":        " wavy",
+	"wavyDouble":  " wavy",
+	"wavyHeavy":   " wavy",
+}
+
+// htmlWriter keeps the state of exporting
+type htmlWriter struct {
+	file   *Docx
+	styles *Styles
+	sb     strings.Builder
+	body   []string // css of the default runs
+	para   []string // css of the default paragraphs
+	lists  []string // tags of the open lists
+}
+
+// WriteHTML exports the body as an html page for previewing, with the
+// properties of paragraphs and runs as inline css, merged table cells
+// as spans and images as data uris.
+func (f *Docx) WriteHTML(w io.Writer) error {
+	s, err := f.lookupStyles()
+	if err != nil {
+		return err
+	}
+	hw := &htmlWriter{file: f, styles: s}
+	hw.body = runCSS(s.ResolveRunProperties(nil, nil))
+	hw.para = paragraphCSS(s.ResolveParagraphProperties(nil))
+	hw.sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<style>\n")
+	hw.sb.WriteString("p, li, h1, h2, h3, h4, h5, h6 {" + strings.Join(hw.para, "; ") + "}\n")
+	hw.sb.WriteString("table {border-collapse: collapse}\ntd {vertical-align: top; padding: 0 5.4pt}\n")
+	hw.sb.WriteString("</style>\n</head>\n<body" + htmlStyle(hw.body) + ">\n")
+	hw.items(f.Document.Body.Items)
+	hw.closeLists(0)
+	hw.sb.WriteString("</body>\n</html>\n")
+	_, err = io.WriteString(w, hw.sb.String())
+	return err
+}
+
+// HTML returns the page of WriteHTML
+func (f *Docx) HTML() string {
+	sb := strings.Builder{}
+	_ = f.WriteHTML(&sb)
+	return sb.String()
+}
+
+// htmlStyle makes the style attribute of css
+func htmlStyle(css []string) string {
+	if len(css) == 0 {
+		return ""
+	}
+	return ` style="` + html.EscapeString(strings.Join(css, "; ")) + `"`
+}
+
+// cssDiff returns the declarations in css but not in base
+func cssDiff(css, base []string) []string {
+	diff := make([]string, 0, len(css))
+	for _, c := range css {
+		found := false
+		for _, b := range base {
+			if b == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, c)
+		}
+	}
+	return diff
+}
+
+// twipsToPt formats twips in points
+func twipsToPt(twips int) string {
+	return strconv.FormatFloat(float64(twips)/20, 'f', -1, 64) + "pt"
+}
+
+// runCSS converts the run properties to css
+func runCSS(rp *RunProperties) []string {
+	css := make([]string, 0, 8)
+	if rp.Fonts != nil {
+		fonts := make([]string, 0, 2)
+		for _, f := range []string{rp.Fonts.ASCII, rp.Fonts.EastAsia} {
+			if f != "" {
+				fonts = append(fonts, cssString(f))
+			}
+		}
+		if len(fonts) > 0 {
+			css = append(css, "font-family: "+strings.Join(fonts, ", "))
+		}
+	}
+	if rp.Size != nil {
+		if sz, err := strconv.ParseFloat(rp.Size.Val, 64); err == nil {
+			css = append(css, "font-size: "+strconv.FormatFloat(sz/2, 'f', -1, 64)+"pt")
+		}
+	}
+	if rp.Color != nil && cssColor(rp.Color.Val) != "" {
+		css = append(css, "color: "+cssColor(rp.Color.Val))
+	}
+	if rp.Bold != nil {
+		css = append(css, "font-weight: bold")
+	}
+	if rp.Italic != nil {
+		css = append(css, "font-style: italic")
+	}
+	if rp.Underline != nil && rp.Underline.Val != "" && rp.Underline.Val != "none" {
+		css = append(css, "text-decoration: underline"+htmlUnderlines[rp.Underline.Val])
+	}
+	if rp.Strike != nil && rp.Strike.Val != "0" && rp.Strike.Val != "false" {
+		css = append(css, "text-decoration: line-through")
+	}
+	if rp.Highlight != nil && htmlHighlights[rp.Highlight.Val] != "" {
+		css = append(css, "background-color: "+htmlHighlights[rp.Highlight.Val])
+	} else if rp.Shade != nil && cssColor(rp.Shade.Fill) != "" {
+		css = append(css, "background-color: "+cssColor(rp.Shade.Fill))
+	}
+	if rp.Vanish != nil && rp.Vanish.Val != "0" && rp.Vanish.Val != "false" {
+		css = append(css, "display: none")
+	}
+	if rp.VertAlign != nil {
+		switch rp.VertAlign.Val {
+		case "superscript":
+			css = append(css, "vertical-align: super", "font-size: smaller")
+		case "subscript":
+			css = append(css, "vertical-align: sub", "font-size: smaller")
+		}
+	}
+	return css
+}
+
+// paragraphCSS converts the paragraph properties to css
+func paragraphCSS(pp *ParagraphProperties) []string {
+	css := make([]string, 0, 8)
+	if pp.Justification != nil {
+		switch pp.Justification.Val {
+		case "start", "left":
+			css = append(css, "text-align: left")
+		case "center":
+			css = append(css, "text-align: center")
+		case "end", "right":
+			css = append(css, "text-align: right")
+		case "both", "distribute":
+			css = append(css, "text-align: justify")
+		}
+	}
+	if pp.Ind != nil {
+		if pp.Ind.Left != 0 {
+			css = append(css, "margin-left: "+twipsToPt(pp.Ind.Left))
+		}
+		if pp.Ind.FirstLine != 0 {
+			css = append(css, "text-indent: "+twipsToPt(pp.Ind.FirstLine))
+		} else if pp.Ind.Hanging != 0 {
+			css = append(css, "text-indent: "+twipsToPt(-pp.Ind.Hanging))
+		}
+	}
+	before, after := 0, 0
+	if pp.Spacing != nil {
+		before = pp.Spacing.Before
+		if pp.Spacing.After != nil {
+			after = *pp.Spacing.After
+		}
+		if pp.Spacing.Line > 0 {
+			switch pp.Spacing.LineRule {
+			case "exact", "atLeast":
+				css = append(css, "line-height: "+twipsToPt(pp.Spacing.Line))
+			default:
+				css = append(css, "line-height: "+strconv.FormatFloat(float64(pp.Spacing.Line)/240, 'f', -1, 64))
+			}
+		}
+	}
+	css = append(css, "margin-top: "+twipsToPt(before), "margin-bottom: "+twipsToPt(after))
+	if pp.Shade != nil && cssColor(pp.Shade.Fill) != "" {
+		css = append(css, "background-color: "+cssColor(pp.Shade.Fill))
+	}
+	return css
+}
+
+func (hw *htmlWriter) items(items []interface{}) {
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			hw.paragraph(o)
+		case *Table:
+			hw.closeLists(0)
+			hw.table(o)
+		}
+	}
+}
+
+// paragraph writes p as a heading, a list item or a paragraph
+func (hw *htmlWriter) paragraph(p *Paragraph) {
+	runs := runCSS(hw.styles.ResolveRunProperties(p, nil))
+	css := append(cssDiff(paragraphCSS(hw.styles.ResolveParagraphProperties(p)), hw.para), cssDiff(runs, hw.body)...)
+	if p.Properties != nil && p.Properties.NumPr != nil && p.Properties.NumPr.NumID != nil && p.Properties.NumPr.NumID.Val != 0 {
+		// the browser indents the lists
+		li := make([]string, 0, len(css))
+		for _, c := range css {
+			if !strings.HasPrefix(c, "margin-left:") && !strings.HasPrefix(c, "text-indent:") {
+				li = append(li, c)
+			}
+		}
+		hw.listItem(p.Properties.NumPr)
+		hw.sb.WriteString("<li" + htmlStyle(li) + ">")
+		hw.inline(p, p.Children, runs)
+		return
+	}
+	hw.closeLists(0)
+	tag := "p"
+	if lv := hw.file.HeadingLevel(p); lv > 0 && lv <= 6 {
+		tag = "h" + strconv.Itoa(lv)
+	}
+	hw.sb.WriteString("<" + tag + htmlStyle(css) + ">")
+	if len(p.Children) == 0 {
+		hw.sb.WriteString("<br>") // keep the height of empty paragraphs
+	}
+	hw.inline(p, p.Children, runs)
+	hw.sb.WriteString("</" + tag + ">\n")
+}
+
+// listItem opens or closes the lists before the item of numpr
+func (hw *htmlWriter) listItem(numpr *NumPr) {
+	level := 0
+	if numpr.Ilvl != nil {
+		level = numpr.Ilvl.Val
+	}
+	tag, attr := "ul", ""
+	if n, err := hw.file.Numbering(); err == nil {
+		if num := n.Num(numpr.NumID.Val); num != nil {
+			if l := num.Level(level); l != nil && l.NumFmt != nil && l.NumFmt.Val != LIST_BULLET && l.NumFmt.Val != "none" {
+				tag = "ol"
+				switch l.NumFmt.Val {
+				case LIST_LOWER_LETTER:
+					attr = ` type="a"`
+				case LIST_UPPER_LETTER:
+					attr = ` type="A"`
+				case LIST_LOWER_ROMAN:
+					attr = ` type="i"`
+				case LIST_UPPER_ROMAN:
+					attr = ` type="I"`
+				}
+				start := 1
+				if l.Start != nil {
+					start = l.Start.Val
+				}
+				for _, o := range num.LvlOverrides {
+					if o.Ilvl == level && o.StartOverride != nil {
+						start = o.StartOverride.Val
+					}
+				}
+				if start != 1 {
+					attr += ` start="` + strconv.Itoa(start) + `"`
+				}
+			}
+		}
+	}
+	hw.closeLists(level + 1)
+	if len(hw.lists) == level+1 {
+		if hw.lists[level] == tag {
+			hw.sb.WriteString("</li>\n")
+			return
+		}
+		hw.closeLists(level)
+	}
+	for len(hw.lists) < level+1 {
+		hw.sb.WriteString("<" + tag + attr + ">\n")
+		hw.lists = append(hw.lists, tag)
+	}
+}
+
+// closeLists closes the lists deeper than level
+func (hw *htmlWriter) closeLists(level int) {
+	for len(hw.lists) > level {
+		hw.sb.WriteString("</li>\n</" + hw.lists[len(hw.lists)-1] + ">\n")
+		hw.lists = hw.lists[:len(hw.lists)-1]
+	}
+}
+
+// inline writes the children of p, whose runs are based on css
+func (hw *htmlWriter) inline(p *Paragraph, children []interface{}, css []string) {
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			hw.run(p, o, css, "")
+		case *Hyperlink:
+			href := "#" + o.Anchor
+			if o.Anchor == "" {
+				href, _ = hw.file.ReferTarget(o.ID)
+			}
+			// the links like javascript: are written without href
+			if href = safeHref(href); href != "" {
+				hw.sb.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+			} else {
+				hw.sb.WriteString("<a>")
+			}
+			hw.run(p, &o.Run, css, o.Run.InstrText)
+			hw.sb.WriteString("</a>")
+		case *BookmarkStart:
+			hw.sb.WriteString(`<a id="` + html.EscapeString(o.Name) + `"></a>`)
+		case *SimpleField:
+			hw.inline(p, o.Children, css)
+		case *Revision:
+			if o.IsInsertion() {
+				hw.inline(p, o.Children, css)
+			}
+		}
+	}
+}
+
+// run writes the texts, breaks and images of r with prefix as its first text
+func (hw *htmlWriter) run(p *Paragraph, r *Run, base []string, prefix string) {
+	css := cssDiff(runCSS(hw.styles.ResolveRunProperties(p, r)), base)
+	var open, closing string
+	span := make([]string, 0, len(css))
+	for _, c := range css {
+		switch c {
+		case "font-weight: bold":
+			open, closing = open+"<strong>", "</strong>"+closing
+		case "font-style: italic":
+			open, closing = open+"<em>", "</em>"+closing
+		case "text-decoration: line-through":
+			open, closing = open+"<s>", "</s>"+closing
+		default:
+			span = append(span, c)
+		}
+	}
+	if len(span) > 0 {
+		open, closing = open+"<span"+htmlStyle(span)+">", "</span>"+closing
+	}
+	hw.sb.WriteString(open)
+	hw.sb.WriteString(html.EscapeString(prefix))
+	for _, c := range r.Children {
+		switch x := c.(type) {
+		case *Text:
+			hw.sb.WriteString(html.EscapeString(x.Text))
+		case *Tab:
+			hw.sb.WriteString("&emsp;")
+		case *BarterRabbet:
+			if x.Type == "page" {
+				hw.sb.WriteString(`<br style="page-break-before: always">`)
+			} else {
+				hw.sb.WriteString("<br>")
+			}
+		case *Drawing:
+			hw.image(x)
+		}
+	}
+	hw.sb.WriteString(closing)
+}
+
+// image writes the picture in d as a data uri
+func (hw *htmlWriter) image(d *Drawing) {
+	embed, alt, ext := d.picture()
+	if embed == "" {
+		return
+	}
+	target, err := hw.file.ReferTarget(embed)
+	if err != nil {
+		return
+	}
+	m := hw.file.Media(path.Base(target))
+	if m == nil {
+		return
+	}
+	mime := extContentTypes[strings.ToLower(strings.TrimPrefix(path.Ext(m.Name), "."))]
+	if mime == "" {
+		mime = CONTENT_TYPE_OCTET_STREAM
+	}
+	hw.sb.WriteString(`<img src="data:` + mime + ";base64," + base64.StdEncoding.EncodeToString(m.Data) + `"`)
+	if ext != nil {
+		// 9525 EMU per pixel
+		hw.sb.WriteString(` width="` + strconv.FormatInt(ext.CX/9525, 10) + `" height="` + strconv.FormatInt(ext.CY/9525, 10) + `"`)
+	}
+	hw.sb.WriteString(` alt="` + html.EscapeString(alt) + `">`)
+}
+
+// tableBorder returns the css of the cell borders of t, or "" if none
+func (hw *htmlWriter) tableBorder(t *Table) string {
+	var borders *WTableBorders
+	if t.TableProperties != nil {
+		borders = t.TableProperties.TableBorders
+		if borders == nil && t.TableProperties.Style != nil {
+			for _, sd := range hw.styles.chain(t.TableProperties.Style.Val) {
+				if sd.TableProperties != nil && sd.TableProperties.TableBorders != nil {
+					borders = sd.TableProperties.TableBorders
+				}
+			}
+		}
+	}
+	if borders == nil {
+		return ""
+	}
+	for _, b := range []*WTableBorder{borders.InsideH, borders.InsideV, borders.Top, borders.Left, borders.Bottom, borders.Right} {
+		if b == nil || b.Val == "" || b.Val == "none" || b.Val == "nil" {
+			continue
+		}
+		color := cssColor(b.Color)
+		if color == "" {
+			color = "#000000"
+		}
+		style := "solid"
+		if b.Val == "double" {
+			style = "double"
+		}
+		width := "1px"
+		if b.Size > 0 {
+			width = strconv.FormatFloat(float64(b.Size)/8, 'f', -1, 64) + "pt"
+		}
+		return "border: " + width + " " + style + " " + color
+	}
+	return ""
+}
+
+// table writes t with the merged cells as spans
+func (hw *htmlWriter) table(t *Table) {
+	border := hw.tableBorder(t)
+	css := make([]string, 0, 2)
+	if t.TableProperties != nil && t.TableProperties.Justification != nil && t.TableProperties.Justification.Val == "center" {
+		css = append(css, "margin-left: auto", "margin-right: auto")
+	}
+	// the grid column of each cell
+	cols := make([][]int, len(t.TableRows))
+	for i, tr := range t.TableRows {
+		col := 0
+		cols[i] = make([]int, len(tr.TableCells))
+		for j, tc := range tr.TableCells {
+			cols[i][j] = col
+			col += tc.gridSpan()
+		}
+	}
+	hw.sb.WriteString("<table" + htmlStyle(css) + ">\n")
+	for i, tr := range t.TableRows {
+		hw.sb.WriteString("<tr>\n")
+		for j, tc := range tr.TableCells {
+			pr := tc.TableCellProperties
+			if pr != nil && pr.VMerge != nil && pr.VMerge.Val != "restart" {
+				continue // merged into the cell above
+			}
+			attr := ""
+			if span := tc.gridSpan(); span > 1 {
+				attr += ` colspan="` + strconv.Itoa(span) + `"`
+			}
+			if pr != nil && pr.VMerge != nil {
+				span := 1
+			rows:
+				for _, next := range t.TableRows[i+1:] {
+					for k, nc := range next.TableCells {
+						if cols[i+span][k] != cols[i][j] {
+							continue
+						}
+						if nc.TableCellProperties == nil || nc.TableCellProperties.VMerge == nil || nc.TableCellProperties.VMerge.Val == "restart" {
+							break rows
+						}
+						span++
+						continue rows
+					}
+					break
+				}
+				if span > 1 {
+					attr += ` rowspan="` + strconv.Itoa(span) + `"`
+				}
+			}
+			css := make([]string, 0, 4)
+			if border != "" {
+				css = append(css, border)
+			}
+			if pr != nil {
+				if pr.TableCellWidth != nil && pr.TableCellWidth.W > 0 {
+					switch pr.TableCellWidth.Type {
+					case "dxa":
+						css = append(css, "width: "+twipsToPt(int(pr.TableCellWidth.W)))
+					case "pct":
+						css = append(css, "width: "+strconv.FormatFloat(float64(pr.TableCellWidth.W)/50, 'f', -1, 64)+"%")
+					}
+				}
+				if pr.Shade != nil && cssColor(pr.Shade.Fill) != "" {
+					css = append(css, "background-color: "+cssColor(pr.Shade.Fill))
+				}
+				if pr.VAlign != nil {
+					switch pr.VAlign.Val {
+					case "center":
+						css = append(css, "vertical-align: middle")
+					case "bottom":
+						css = append(css, "vertical-align: bottom")
+					}
+				}
+			}
+			hw.sb.WriteString("<td" + attr + htmlStyle(css) + ">\n")
+			hw.items(tc.Items)
+			hw.closeLists(0)
+			hw.sb.WriteString("</td>\n")
+		}
+		hw.sb.WriteString("</tr>\n")
+	}
+	hw.sb.WriteString("</table>\n")
+}
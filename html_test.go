@@ -0,0 +1,90 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	w := New().WithDefaultTheme()
+	_, err := w.AddHeading("Title", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := w.AddParagraph().Justification("center")
+	p.AddText("red").Color("FF0000").Size("28")
+	p.AddText(" bold").Bold()
+	p.AddText(" <mark>").Highlight("yellow").Underline("double")
+	p.AddLink("google", "http://google.com")
+	p.AddLink("evil", "javascript:alert(document.cookie)")
+	p.AddText("x").Color("red;background-image:url(https://evil.example/t)").Font("a';x:url(y)", "", "")
+	decimal, err := w.AddList(LIST_DECIMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().ListItem(decimal, 0).AddText("first")
+	w.AddParagraph().ListItem(decimal, 1).AddText("nested")
+	w.AddParagraph().ListItem(decimal, 0).AddText("second")
+	_, err = w.AddParagraph().AddInlineDrawingFrom("testdata/fumiama.JPG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl := w.AddTable(3, 3)
+	tbl.TableRows[0].TableCells[0].TableCellProperties.GridSpan = &WGridSpan{Val: 2}
+	tbl.TableRows[0].TableCells = append(tbl.TableRows[0].TableCells[:1], tbl.TableRows[0].TableCells[2])
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("wide")
+	tbl.TableRows[0].TableCells[1].TableCellProperties.VMerge = &WvMerge{Val: "restart"}
+	tbl.TableRows[0].TableCells[1].AddParagraph().AddText("tall")
+	tbl.TableRows[1].TableCells[2].TableCellProperties.VMerge = &WvMerge{}
+	tbl.TableRows[2].TableCells[2].TableCellProperties.VMerge = &WvMerge{}
+
+	page := w.HTML()
+	for _, s := range []string{
+		"<h1",
+		">Title</h1>",
+		`<p style="text-align: center">`,
+		`<span style="font-size: 14pt; color: #FF0000">red</span>`,
+		"<strong> bold</strong>",
+		`<span style="text-decoration: underline double; background-color: yellow"> &lt;mark&gt;</span>`,
+		`<a href="http://google.com">`,
+		"<ol>\n<li>first<ol type=\"a\">\n<li>nested</li>\n</ol>\n</li>\n<li>second</li>\n</ol>\n",
+		`<img src="data:image/jpeg;base64,`,
+		`<td colspan="2" style="border: 0.5pt solid #000000">`,
+		`<td rowspan="3" style="border: 0.5pt solid #000000">`,
+	} {
+		if !strings.Contains(page, s) {
+			t.Fatal("expected", s, "in", page)
+		}
+	}
+	for _, s := range []string{"javascript:", "red;", "evil.example", "a'"} {
+		if strings.Contains(page, s) {
+			t.Fatal("unexpected", s, "in", page)
+		}
+	}
+	if !strings.Contains(page, `font-family: &#34;a\27 \3b x\3a url\28 y\29 &#34;`) {
+		t.Fatal("expected the escaped font in", page)
+	}
+	if n := strings.Count(page, "<td"); n != 6 {
+		t.Fatal("expected 6 cells but has", n)
+	}
+}
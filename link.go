@@ -64,17 +64,25 @@ func (f *Docx) addImageRelation(m Media) string {
 	return rel.ID
 }
 
-// ReferTarget gets the target for a reference
+// ReferTarget gets the target for a reference in the document
+// or in the parts like headers
 func (f *Docx) ReferTarget(id string) (string, error) {
 	for _, a := range f.docRelation.Relationship {
 		if a.ID == id {
 			return a.Target, nil
 		}
 	}
+	for _, rels := range f.partRels {
+		for _, a := range rels.Relationship {
+			if a.ID == id {
+				return a.Target, nil
+			}
+		}
+	}
 	return "", ErrRefIDNotFound
 }
 
-// ReferID gets the rId from target
+// ReferID gets the rId from target in the document relationships
 func (f *Docx) ReferID(target string) (string, error) {
 	for _, a := range f.docRelation.Relationship {
 		if a.Target == target {
@@ -83,3 +91,18 @@ func (f *Docx) ReferID(target string) (string, error) {
 	}
 	return "", ErrRefIDNotFound
 }
+
+// when adding a part like header we need to store a reference in the relationship field
+//
+//	this func is not thread-safe
+func (f *Docx) addPartRelation(typ, target string) string {
+	rel := Relationship{
+		ID:     "rId" + strconv.Itoa(int(atomic.AddUintptr(&f.rID, 1))),
+		Type:   typ,
+		Target: target,
+	}
+
+	f.docRelation.Relationship = append(f.docRelation.Relationship, rel)
+
+	return rel.ID
+}
@@ -0,0 +1,659 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Template renders documents from a docx with placeholders like a mail merge.
+//
+//	{{name}}、{{a.b}}：替换为数据中的值，数据可以是 map 或 struct，
+//		struct 的字段名不区分大小写，也可由 `docx:"name"` 标签指定，{{.}} 为当前的值。
+//	{{image name}}：替换为图片，值为图片数据 []byte 或文件路径 string。
+//	{{range list}}、{{if cond}}、{{else}}、{{end}}：独占一段时重复或选择其间的段落和表格；
+//		位于表格行中时重复或选择从此行到 {{end}} 所在行的所有行。
+//
+// The placeholders can be split into several runs, and the result takes
+// the format of the first one. The document given is never changed.
+type Template struct {
+	data []byte
+}
+
+// templateTagRegex matches {{xxx}}
+var templateTagRegex = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// templateBlockRegex matches a paragraph of a block tag
+var templateBlockRegex = regexp.MustCompile(`^\s*\{\{\s*(range|if|else|end)\b\s*([^{}]*?)\s*\}\}\s*$`)
+
+// templateRowRegex matches the block tags in a table row
+var templateRowRegex = regexp.MustCompile(`\{\{\s*(range|if|end)\b\s*([^{}]*?)\s*\}\}`)
+
+// templateImageRegex matches the image placeholders after expanding
+var templateImageRegex = regexp.MustCompile(`\x{E000}([0-9]+)\x{E001}`)
+
+// NewTemplate makes a snapshot of f as the template
+func NewTemplate(f *Docx) (*Template, error) {
+	var buf bytes.Buffer
+	_, err := f.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{data: buf.Bytes()}, nil
+}
+
+// Render makes a new document from the template with data,
+// which can be a map with string keys, a struct or a pointer to them
+func (t *Template) Render(data interface{}) (*Docx, error) {
+	f, err := Parse(bytes.NewReader(t.data), int64(len(t.data)))
+	if err != nil {
+		return nil, err
+	}
+	scopes := []reflect.Value{reflect.ValueOf(data)}
+	f.Document.Body.Items, err = f.renderItems(f.Document.Body.Items, scopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range f.headers {
+		h.Items, err = f.renderItems(h.Items, scopes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, ft := range f.footers {
+		ft.Items, err = f.renderItems(ft.Items, scopes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// renderItems renders the body items with the block tags in their own paragraphs
+func (f *Docx) renderItems(items []interface{}, scopes []reflect.Value) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(items))
+	for i := 0; i < len(items); i++ {
+		kind, arg := blockTag(items[i])
+		if kind == "else" || kind == "end" {
+			return nil, errors.New("template: unexpected {{" + kind + "}}")
+		}
+		if kind == "" {
+			err := f.renderItem(items[i], scopes)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, items[i])
+			continue
+		}
+		// find the {{else}} and {{end}} of the block
+		els, end, depth := -1, -1, 0
+		for j := i + 1; j < len(items) && end < 0; j++ {
+			switch k, _ := blockTag(items[j]); k {
+			case "range", "if":
+				depth++
+			case "else":
+				if depth == 0 && els < 0 {
+					els = j
+				}
+			case "end":
+				if depth == 0 {
+					end = j
+				}
+				depth--
+			}
+		}
+		if end < 0 {
+			return nil, errors.New("template: no {{end}} for {{" + kind + " " + arg + "}}")
+		}
+		v, err := lookupTemplateValue(scopes, arg)
+		if err != nil {
+			return nil, err
+		}
+		body, elsebody := items[i+1:end], []interface{}(nil)
+		if els >= 0 {
+			body, elsebody = items[i+1:els], items[els+1:end]
+		}
+		var rendered []interface{}
+		switch {
+		case kind == "if" && isTemplateTrue(v):
+			rendered, err = f.renderItems(body, scopes)
+		case kind == "if" || (kind == "range" && !isTemplateTrue(v)):
+			rendered, err = f.renderItems(elsebody, scopes)
+		default:
+			v = indirectTemplateValue(v)
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return nil, errors.New("template: cannot range over " + arg)
+			}
+			for k := 0; k < v.Len() && err == nil; k++ {
+				var cloned, r []interface{}
+				cloned, err = f.cloneItems(body, k > 0)
+				if err != nil {
+					break
+				}
+				r, err = f.renderItems(cloned, append(scopes[:len(scopes):len(scopes)], v.Index(k)))
+				rendered = append(rendered, r...)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rendered...)
+		i = end
+	}
+	return out, nil
+}
+
+// blockTag returns the kind and argument if item is a paragraph of a block tag
+func blockTag(item interface{}) (string, string) {
+	p, ok := item.(*Paragraph)
+	if !ok {
+		return "", ""
+	}
+	m := templateBlockRegex.FindStringSubmatch(paragraphText(p.Children))
+	if m == nil {
+		return "", ""
+	}
+	return m[1], m[2]
+}
+
+// renderItem renders the placeholders in a paragraph or table
+func (f *Docx) renderItem(item interface{}, scopes []reflect.Value) (err error) {
+	switch o := item.(type) {
+	case *Paragraph:
+		err = f.renderParagraph(o, scopes)
+		if err != nil {
+			return
+		}
+		walkTextBoxParagraphs(o, func(p *Paragraph) bool {
+			err = f.renderParagraph(p, scopes)
+			return err == nil
+		})
+	case *Table:
+		o.TableRows, err = f.renderRows(o.TableRows, scopes)
+	}
+	return
+}
+
+// renderRows renders the table rows with the block tags inside them
+func (f *Docx) renderRows(rows []*WTableRow, scopes []reflect.Value) ([]*WTableRow, error) {
+	out := make([]*WTableRow, 0, len(rows))
+	for i := 0; i < len(rows); i++ {
+		tags := templateRowRegex.FindAllStringSubmatch(rowText(rows[i]), -1)
+		if len(tags) == 0 || tags[0][1] == "end" {
+			if len(tags) > 0 {
+				return nil, errors.New("template: unexpected {{end}} in table")
+			}
+			err := f.renderCells(rows[i], scopes)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, rows[i])
+			continue
+		}
+		kind, arg := tags[0][1], tags[0][2]
+		end, depth := -1, 0
+		for j := i; j < len(rows) && end < 0; j++ {
+			rowtags := tags[1:]
+			if j > i {
+				rowtags = templateRowRegex.FindAllStringSubmatch(rowText(rows[j]), -1)
+			}
+			for _, tag := range rowtags {
+				switch tag[1] {
+				case "range", "if":
+					depth++
+				case "end":
+					if depth == 0 && end < 0 {
+						end = j
+					}
+					depth--
+				}
+			}
+		}
+		if end < 0 {
+			return nil, errors.New("template: no {{end}} for {{" + kind + " " + arg + "}} in table")
+		}
+		v, err := lookupTemplateValue(scopes, arg)
+		if err != nil {
+			return nil, err
+		}
+		n := 0
+		if kind == "if" && isTemplateTrue(v) {
+			n = 1
+		} else if kind == "range" {
+			v = indirectTemplateValue(v)
+			if v.IsValid() && v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return nil, errors.New("template: cannot range over " + arg)
+			}
+			if v.IsValid() {
+				n = v.Len()
+			}
+		}
+		for k := 0; k < n; k++ {
+			block, err := f.cloneRows(rows[i:end+1], k > 0)
+			if err != nil {
+				return nil, err
+			}
+			stripRowTag(block[0], tags[0][0])
+			stripRowTag(block[len(block)-1], "end")
+			sc := scopes
+			if kind == "range" {
+				sc = append(scopes[:len(scopes):len(scopes)], v.Index(k))
+			}
+			block, err = f.renderRows(block, sc)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, block...)
+		}
+		i = end
+	}
+	return out, nil
+}
+
+// rowText joins the texts of the paragraphs in the row
+// without those in the nested tables
+func rowText(tr *WTableRow) string {
+	sb := strings.Builder{}
+	for _, tc := range tr.TableCells {
+		for _, p := range tc.Paragraphs() {
+			sb.WriteString(paragraphText(p.Children))
+		}
+	}
+	return sb.String()
+}
+
+// stripRowTag removes the first tag (or the last {{end}}) in the row
+func stripRowTag(tr *WTableRow, tag string) {
+	cells := tr.TableCells
+	for i := range cells {
+		if tag == "end" {
+			i = len(cells) - 1 - i
+		}
+		ps := cells[i].Paragraphs()
+		for j := range ps {
+			if tag == "end" {
+				j = len(ps) - 1 - j
+			}
+			p := ps[j]
+			n := 0
+			p.replace(func(s string) [][]int {
+				locs := templateRowRegex.FindAllStringSubmatchIndex(s, -1)
+				for k := range locs {
+					if tag == "end" {
+						k = len(locs) - 1 - k
+					}
+					m := locs[k]
+					if (tag == "end" && s[m[2]:m[3]] == "end") || (tag != "end" && s[m[0]:m[1]] == tag) {
+						n++
+						return locs[k : k+1]
+					}
+				}
+				return nil
+			}, func(string, []int) string {
+				return ""
+			})
+			if n > 0 {
+				return
+			}
+		}
+	}
+}
+
+// renderCells renders the paragraphs and the nested tables in the cells of the row
+func (f *Docx) renderCells(tr *WTableRow, scopes []reflect.Value) error {
+	for _, tc := range tr.TableCells {
+		items, err := f.renderItems(tc.Items, scopes)
+		if err != nil {
+			return err
+		}
+		tc.Items = items
+		if len(items) == 0 {
+			tc.Items = append(tc.Items, &Paragraph{file: f}) // a cell must end with a paragraph
+		} else if _, ok := items[len(items)-1].(*Paragraph); !ok {
+			tc.Items = append(tc.Items, &Paragraph{file: f})
+		}
+	}
+	return nil
+}
+
+// renderParagraph replaces the placeholders in p with the values
+func (f *Docx) renderParagraph(p *Paragraph, scopes []reflect.Value) (err error) {
+	var images []reflect.Value
+	p.replace(func(s string) [][]int {
+		return templateTagRegex.FindAllStringSubmatchIndex(s, -1)
+	}, func(s string, m []int) string {
+		if err != nil {
+			return ""
+		}
+		tag := s[m[2]:m[3]]
+		if fields := strings.Fields(tag); len(fields) == 2 && fields[0] == "image" {
+			var v reflect.Value
+			v, err = lookupTemplateValue(scopes, fields[1])
+			images = append(images, v)
+			return "\uE000" + strconv.Itoa(len(images)-1) + "\uE001"
+		}
+		if m := templateBlockRegex.FindStringSubmatch(s[m[0]:m[1]]); m != nil {
+			err = errors.New("template: {{" + m[1] + "}} must be in its own paragraph or a table row")
+			return ""
+		}
+		var v reflect.Value
+		v, err = lookupTemplateValue(scopes, tag)
+		v = indirectTemplateValue(v)
+		if !v.IsValid() {
+			return ""
+		}
+		return fmt.Sprint(v.Interface())
+	})
+	if err != nil || len(images) == 0 {
+		return
+	}
+	for _, c := range p.Children {
+		r, ok := c.(*Run)
+		if !ok {
+			continue
+		}
+		children := make([]interface{}, 0, len(r.Children)+2)
+		for _, x := range r.Children {
+			t, ok := x.(*Text)
+			if !ok || !templateImageRegex.MatchString(t.Text) {
+				children = append(children, x)
+				continue
+			}
+			last := 0
+			for _, m := range templateImageRegex.FindAllStringSubmatchIndex(t.Text, -1) {
+				if m[0] > last {
+					children = append(children, newText(t.Text[last:m[0]]))
+				}
+				last = m[1]
+				i, _ := strconv.Atoi(t.Text[m[2]:m[3]])
+				var d *Drawing
+				d, err = f.templateImage(p, images[i])
+				if err != nil {
+					return
+				}
+				if d != nil {
+					children = append(children, d)
+				}
+			}
+			if last < len(t.Text) {
+				children = append(children, newText(t.Text[last:]))
+			}
+		}
+		r.Children = children
+	}
+	return
+}
+
+// templateImage makes an inline drawing of v which is []byte or a file path
+func (f *Docx) templateImage(p *Paragraph, v reflect.Value) (*Drawing, error) {
+	v = indirectTemplateValue(v)
+	if !v.IsValid() {
+		return nil, nil
+	}
+	n := len(p.Children)
+	var err error
+	switch x := v.Interface().(type) {
+	case []byte:
+		if len(x) == 0 {
+			return nil, nil
+		}
+		_, err = p.AddInlineDrawing(x)
+	case string:
+		if x == "" {
+			return nil, nil
+		}
+		_, err = p.AddInlineDrawingFrom(x)
+	default:
+		return nil, errors.New("template: invalid image " + v.Type().String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	// move the drawing out of the run added
+	r := p.Children[n].(*Run)
+	p.Children = p.Children[:n]
+	return r.Children[0].(*Drawing), nil
+}
+
+// lookupTemplateValue finds the value of path like a.b from the innermost scope,
+// and . or .a only looks up the innermost one
+func lookupTemplateValue(scopes []reflect.Value, path string) (reflect.Value, error) {
+	if path == "." {
+		return scopes[len(scopes)-1], nil
+	}
+	names := strings.Split(path, ".")
+	candidates := scopes
+	if names[0] == "" {
+		names = names[1:]
+		candidates = scopes[len(scopes)-1:]
+	}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		v, ok := templateField(candidates[i], names[0])
+		if !ok {
+			continue
+		}
+		for _, name := range names[1:] {
+			v, ok = templateField(v, name)
+			if !ok {
+				return reflect.Value{}, errors.New("template: no value for " + path)
+			}
+		}
+		return v, nil
+	}
+	return reflect.Value{}, errors.New("template: no value for " + path)
+}
+
+// templateField gets the value of name in a map or struct
+func templateField(v reflect.Value, name string) (reflect.Value, bool) {
+	v = indirectTemplateValue(v)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		x := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		return x, x.IsValid()
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			tag := sf.Tag.Get("docx")
+			if tag == name || (tag == "" && strings.EqualFold(sf.Name, name)) {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// indirectTemplateValue goes through the pointers and interfaces
+func indirectTemplateValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isTemplateTrue is false on invalid, zero numbers, false and empty values
+func isTemplateTrue(v reflect.Value) bool {
+	v = indirectTemplateValue(v)
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() > 0
+	case reflect.Struct:
+		return true
+	default:
+		return !v.IsZero()
+	}
+}
+
+// templateCloneRoot declares all the namespaces that may appear in the items
+var templateCloneRoot = func() string {
+	sb := strings.Builder{}
+	sb.WriteString("<w:body")
+	for ns, prefix := range knownPrefixes {
+		if prefix == "" {
+			continue
+		}
+		sb.WriteString(" xmlns:" + prefix + `="` + ns + `"`)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}()
+
+// cloneItems makes a deep copy of the body items by marshalling them.
+// The bookmarks, comments and notes in the copy are renewed if renew,
+// so that it can be put into the body besides the other copies.
+func (f *Docx) cloneItems(items []interface{}, renew bool) ([]interface{}, error) {
+	var buf bytes.Buffer
+	buf.WriteString(templateCloneRoot)
+	e := xml.NewEncoder(&buf)
+	for _, it := range items {
+		err := e.Encode(it)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("</w:body>")
+	b := Body{file: f}
+	err := xml.NewDecoder(&buf).Decode(&b)
+	if err != nil {
+		return nil, err
+	}
+	if renew {
+		f.renewClone(b.Items)
+	}
+	return b.Items, nil
+}
+
+// cloneRows makes a deep copy of the table rows like cloneItems
+func (f *Docx) cloneRows(rows []*WTableRow, renew bool) ([]*WTableRow, error) {
+	items, err := f.cloneItems([]interface{}{&Table{TableRows: rows}}, renew)
+	if err != nil {
+		return nil, err
+	}
+	return items[0].(*Table).TableRows, nil
+}
+
+// renewClone gives the bookmarks in the cloned items new names and ids,
+// which the anchors and the field instructions in them follow, and points
+// the comment ranges and the note references to new copies of them
+func (f *Docx) renewClone(items []interface{}) {
+	bt := f.bookmarkTable()
+	names := make(map[string]string, 8)
+	ids := make(map[int]int, 8)
+	renewID := func(id int) int {
+		n, ok := ids[id]
+		if !ok {
+			n = bt.newID()
+			ids[id] = n
+		}
+		return n
+	}
+	rename := func(name string) string {
+		if n, ok := names[name]; ok {
+			return n
+		}
+		return name
+	}
+	var bookmarks, others func(children []interface{})
+	bookmarks = func(children []interface{}) {
+		for _, c := range children {
+			switch o := c.(type) {
+			case *BookmarkStart:
+				o.ID = renewID(o.ID)
+				n := bt.newName(o.Name)
+				names[o.Name] = n
+				o.Name = n
+			case *BookmarkEnd:
+				o.ID = renewID(o.ID)
+			case *Revision:
+				bookmarks(o.Children)
+			case *SimpleField:
+				bookmarks(o.Children)
+			}
+		}
+	}
+	f.copiedComments = nil // each copy has its own comments
+	comment := func(id int) int {
+		if n, ok := f.copycomment(id, f); ok {
+			return n
+		}
+		return id
+	}
+	run := func(r *Run) {
+		r.InstrText = renamedFieldInstr(r.InstrText, rename)
+		r.DelInstrText = renamedFieldInstr(r.DelInstrText, rename)
+		f.duplicatenotes(r, f)
+		for _, x := range r.Children {
+			if ref, ok := x.(*CommentReference); ok {
+				ref.ID = comment(ref.ID)
+			}
+		}
+	}
+	others = func(children []interface{}) {
+		for _, c := range children {
+			switch o := c.(type) {
+			case *Run:
+				run(o)
+			case *Hyperlink:
+				if o.Anchor != "" {
+					o.Anchor = rename(o.Anchor)
+				}
+				run(&o.Run)
+			case *CommentRangeStart:
+				o.ID = comment(o.ID)
+			case *CommentRangeEnd:
+				o.ID = comment(o.ID)
+			case *Revision:
+				others(o.Children)
+			case *SimpleField:
+				o.Instr = renamedFieldInstr(o.Instr, rename)
+				others(o.Children)
+			}
+		}
+	}
+	bookmarks(items)
+	walkParagraphs(items, func(p *Paragraph) bool {
+		bookmarks(p.Children)
+		return true
+	})
+	walkParagraphs(items, func(p *Paragraph) bool {
+		others(p.Children)
+		return true
+	})
+}
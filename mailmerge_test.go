@@ -0,0 +1,255 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+type mailMergeItem struct {
+	Name  string `docx:"name"`
+	Price float64
+}
+
+func TestTemplate(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("Dear {{cust")
+	p.AddText("omer.name}},").Bold()
+	w.AddParagraph().AddText("{{if vip}}")
+	w.AddParagraph().AddText("Thank you, VIP {{customer.name}}.")
+	w.AddParagraph().AddText("{{else}}")
+	w.AddParagraph().AddText("Become a VIP.")
+	w.AddParagraph().AddText("{{end}}")
+	w.AddParagraph().AddText("{{range notes}}")
+	w.AddParagraph().AddText("- {{.}} for {{customer.name}}")
+	w.AddParagraph().AddText("{{end}}")
+	tbl := w.AddTable(3, 2)
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("Item")
+	tbl.TableRows[1].TableCells[0].AddParagraph().AddText("{{range items}}{{name}}")
+	tbl.TableRows[1].TableCells[1].AddParagraph().AddText("{{Price}}{{end}}")
+	tbl.TableRows[2].TableCells[0].AddParagraph().AddText("Total")
+	tbl.TableRows[2].TableCells[1].AddParagraph().AddText("{{total}}")
+	w.AddParagraph().AddText("Logo: {{image logo}}!")
+	w.AddHeader().AddParagraph().AddText("To {{customer.name}}")
+
+	tmpl, err := NewTemplate(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logo, err := os.ReadFile("testdata/fumiamayoko.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type customer struct{ Name string }
+	for _, tc := range []struct {
+		data   map[string]interface{}
+		expect []string
+		rows   int
+	}{
+		{
+			data: map[string]interface{}{
+				"customer": &customer{Name: "Alice"},
+				"vip":      true,
+				"notes":    []string{"a", "b"},
+				"items":    []mailMergeItem{{"pen", 1.5}, {"ink", 2}},
+				"total":    3.5,
+				"logo":     logo,
+			},
+			expect: []string{"Dear Alice,", "Thank you, VIP Alice.", "- a for Alice", "- b for Alice"},
+			rows:   4,
+		},
+		{
+			data: map[string]interface{}{
+				"customer": customer{Name: "Bob"},
+				"vip":      false,
+				"notes":    nil,
+				"items":    []*mailMergeItem{},
+				"total":    0,
+				"logo":     "testdata/fumiamayoko.png",
+			},
+			expect: []string{"Dear Bob,", "Become a VIP."},
+			rows:   2,
+		},
+	} {
+		doc, err := tmpl.Render(tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items := doc.Document.Body.Items
+		if len(items) != len(tc.expect)+2 {
+			t.Fatal("expected", len(tc.expect)+2, "items but has", len(items))
+		}
+		for i, s := range tc.expect {
+			if items[i].(*Paragraph).String() != s {
+				t.Fatal("expected", s, "but has", items[i])
+			}
+		}
+		tbl := items[len(tc.expect)].(*Table)
+		if len(tbl.TableRows) != tc.rows {
+			t.Fatal("expected", tc.rows, "rows but has", len(tbl.TableRows))
+		}
+		if tc.rows == 4 {
+			if s := rowText(tbl.TableRows[2]); s != "ink2" {
+				t.Fatal("unexpected row", s)
+			}
+		}
+		img := items[len(items)-1].(*Paragraph)
+		if !strings.HasPrefix(img.String(), "Logo: ![") || !strings.HasSuffix(img.String(), "!") {
+			t.Fatal("expected the logo but has", img)
+		}
+		var buf bytes.Buffer
+		_, err = doc.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(doc.media) != 1 {
+			t.Fatal("expected the logo in media but has", len(doc.media))
+		}
+		if s := doc.headers[0].Items[0].(*Paragraph).String(); s != "To "+tc.expect[0][5:len(tc.expect[0])-1] {
+			t.Fatal("unexpected header", s)
+		}
+	}
+	// the template is not changed
+	if len(w.Document.Body.Items) != 11 || p.String() != "Dear {{customer.name}}," {
+		t.Fatal("expected the template to be kept")
+	}
+	_, err = tmpl.Render(map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "customer.name") {
+		t.Fatal("expected error of missing value but has", err)
+	}
+}
+
+func TestTemplateNestedRows(t *testing.T) {
+	w := New().WithDefaultTheme()
+	tbl := w.AddTable(4, 2)
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("{{range items}}")
+	tbl.TableRows[1].TableCells[0].AddParagraph().AddText("{{name}}")
+	tbl.TableRows[1].TableCells[1].AddParagraph().AddText("{{Price}}")
+	tbl.TableRows[2].TableCells[0].AddParagraph().AddText("{{if ok}}on sale{{end}}")
+	tbl.TableRows[3].TableCells[0].AddParagraph().AddText("{{end}}")
+
+	tmpl, err := NewTemplate(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		ok     bool
+		expect []string
+	}{
+		{true, []string{"", "pen1.5", "on sale", "", "", "ink2", "on sale", ""}},
+		{false, []string{"", "pen1.5", "", "", "ink2", ""}},
+	} {
+		doc, err := tmpl.Render(map[string]interface{}{
+			"items": []mailMergeItem{{"pen", 1.5}, {"ink", 2}},
+			"ok":    tc.ok,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := doc.Document.Body.Items[0].(*Table).TableRows
+		if len(rows) != len(tc.expect) {
+			t.Fatal("expected", len(tc.expect), "rows but has", len(rows))
+		}
+		for i, s := range tc.expect {
+			if rowText(rows[i]) != s {
+				t.Fatal("expected", s, "in row", i, "but has", rowText(rows[i]))
+			}
+		}
+	}
+}
+
+func TestTemplateRenewBlocks(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddParagraph().AddText("{{range items}}")
+	p := w.AddParagraph()
+	r := p.AddText("{{name}}")
+	p.AddBookmark("item")
+	p.AddFootnote("note of {{name}}")
+	_, err := w.AddComment(r, r, "alice", "check it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().AddText("{{end}}")
+
+	tmpl, err := NewTemplate(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := tmpl.Render(map[string]interface{}{
+		"items": []mailMergeItem{{"pen", 1.5}, {"ink", 2}, {"cap", 3}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	_, err = doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]struct{}, 4)
+	ids := make(map[int]struct{}, 4)
+	for _, b := range doc.Bookmarks() {
+		names[b.Name] = struct{}{}
+		ids[b.ID] = struct{}{}
+	}
+	if len(names) != 3 || len(ids) != 3 {
+		t.Fatal("expected 3 distinct bookmarks but has", names, ids)
+	}
+	comments, notes := make(map[int]struct{}, 4), make(map[int]struct{}, 4)
+	walkParagraphs(doc.Document.Body.Items, func(p *Paragraph) bool {
+		for _, c := range p.Children {
+			switch o := c.(type) {
+			case *CommentRangeStart:
+				comments[o.ID] = struct{}{}
+			case *Run:
+				for _, x := range o.Children {
+					if ref, ok := x.(*FootnoteReference); ok {
+						notes[ref.ID] = struct{}{}
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(comments) != 3 || len(doc.Comments().Comments) != 3 {
+		t.Fatal("expected 3 comments but has", len(comments), len(doc.Comments().Comments))
+	}
+	if len(notes) != 3 {
+		t.Fatal("expected 3 footnotes but has", len(notes))
+	}
+	for id := range notes {
+		if doc.Footnotes().Note(id) == nil {
+			t.Fatal("missing footnote", id)
+		}
+	}
+}
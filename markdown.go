@@ -0,0 +1,353 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MarkdownPageBreak is written for the page breaks as markdown has none
+const MarkdownPageBreak = `<div style="page-break-after: always"></div>`
+
+// MarkdownOptions controls how WriteMarkdown exports the images
+type MarkdownOptions struct {
+	MediaDir  string // MediaDir is where the images are saved, and no image is saved if empty
+	MediaLink string // MediaLink is the path of MediaDir used in the links, MediaDir if empty
+}
+
+// markdownWriter keeps the state of exporting
+type markdownWriter struct {
+	file   *Docx
+	opts   MarkdownOptions
+	blocks []string
+	lists  []int // lists are the numIDs of the blocks that are list items
+	saved  map[string]struct{}
+	err    error
+}
+
+// markdownSpan is a piece of inline text with the same emphasis
+type markdownSpan struct {
+	text                 string
+	bold, italic, strike bool
+}
+
+// markdownEscaper escapes the characters having meanings in markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `~`, `\~`,
+)
+
+// markdownDestinationEscaper escapes the characters that cannot be
+// in a link destination in angle brackets
+var markdownDestinationEscaper = strings.NewReplacer(
+	`\`, `\\`, `<`, "%3C", `>`, "%3E", "\n", "%0A",
+)
+
+// markdownDestination returns target as a link destination,
+// in angle brackets if it has spaces or parentheses
+func markdownDestination(target string) string {
+	target = markdownDestinationEscaper.Replace(target)
+	if strings.ContainsAny(target, " \t()") {
+		return "<" + target + ">"
+	}
+	return target
+}
+
+// markdownBlockStartRegex matches the starts of text that will be read as a block
+var markdownBlockStartRegex = regexp.MustCompile(`^(#|-|\+|=|[0-9]+[.)])`)
+
+// WriteMarkdown exports the body as markdown, including headings, emphasis,
+// lists, links, images, tables and page breaks.
+func (f *Docx) WriteMarkdown(w io.Writer, opts MarkdownOptions) error {
+	mw := &markdownWriter{file: f, opts: opts, saved: make(map[string]struct{}, 16)}
+	mw.items(f.Document.Body.Items)
+	if mw.err != nil {
+		return mw.err
+	}
+	sb := strings.Builder{}
+	for i, b := range mw.blocks {
+		if i > 0 {
+			if mw.lists[i] != 0 && mw.lists[i-1] == mw.lists[i] {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(b)
+	}
+	if sb.Len() > 0 {
+		sb.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Markdown exports the body as markdown without saving the images
+func (f *Docx) Markdown() string {
+	sb := strings.Builder{}
+	_ = f.WriteMarkdown(&sb, MarkdownOptions{})
+	return sb.String()
+}
+
+func (mw *markdownWriter) add(block string, list int) {
+	mw.blocks = append(mw.blocks, block)
+	mw.lists = append(mw.lists, list)
+}
+
+func (mw *markdownWriter) items(items []interface{}) {
+	for _, it := range items {
+		switch o := it.(type) {
+		case *Paragraph:
+			mw.paragraph(o)
+		case *Table:
+			mw.table(o)
+		}
+	}
+}
+
+func (mw *markdownWriter) paragraph(p *Paragraph) {
+	prefix, list := "", 0
+	if lv := mw.file.HeadingLevel(p); lv > 0 {
+		prefix = strings.Repeat("#", lv) + " "
+	} else if p.Properties != nil && p.Properties.NumPr != nil &&
+		p.Properties.NumPr.NumID != nil && p.Properties.NumPr.NumID.Val != 0 {
+		ilvl := 0
+		if p.Properties.NumPr.Ilvl != nil {
+			ilvl = p.Properties.NumPr.Ilvl.Val
+		}
+		prefix, list = strings.Repeat("    ", ilvl)+"- ", p.Properties.NumPr.NumID.Val
+		if mw.ordered(p.Properties.NumPr.NumID.Val, ilvl) {
+			prefix = strings.Repeat("    ", ilvl) + "1. "
+		}
+	}
+	// page breaks split the paragraph into blocks
+	for i, s := range strings.Split(mw.inline(p.Children, "\\\n"), "\f") {
+		if i > 0 {
+			mw.add(MarkdownPageBreak, 0)
+		}
+		s = strings.TrimRight(s, "\\\n")
+		if s == "" && (prefix == "" || i > 0) {
+			continue
+		}
+		if markdownBlockStartRegex.MatchString(s) {
+			s = `\` + s
+		}
+		mw.add(prefix+s, list)
+	}
+}
+
+// ordered reports whether the level of num is not a bullet
+func (mw *markdownWriter) ordered(numID, ilvl int) bool {
+	n, err := mw.file.Numbering()
+	if err != nil {
+		return false
+	}
+	num := n.Num(numID)
+	if num == nil {
+		return false
+	}
+	l := num.Level(ilvl)
+	return l != nil && l.NumFmt != nil && l.NumFmt.Val != LIST_BULLET && l.NumFmt.Val != "none"
+}
+
+// inline renders the children of a paragraph, with br as the line break
+// and \f as the page break
+func (mw *markdownWriter) inline(children []interface{}, br string) string {
+	spans := make([]markdownSpan, 0, len(children))
+	for _, c := range children {
+		switch o := c.(type) {
+		case *Run:
+			spans = append(spans, mw.run(o, br)...)
+		case *Hyperlink:
+			text := markdownEscaper.Replace(o.Run.InstrText + runText(&o.Run))
+			target := "#" + o.Anchor
+			if o.Anchor == "" {
+				target, _ = mw.file.ReferTarget(o.ID)
+			}
+			spans = append(spans, markdownSpan{text: "[" + text + "](" + markdownDestination(target) + ")"})
+		case *SimpleField:
+			spans = append(spans, markdownSpan{text: mw.inline(o.Children, br)})
+		case *Revision:
+			if o.IsInsertion() {
+				spans = append(spans, markdownSpan{text: mw.inline(o.Children, br)})
+			}
+		}
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(spans); i++ {
+		s := spans[i]
+		for i+1 < len(spans) && spans[i+1].bold == s.bold && spans[i+1].italic == s.italic && spans[i+1].strike == s.strike {
+			i++
+			s.text += spans[i].text
+		}
+		marks := ""
+		if s.bold {
+			marks += "**"
+		}
+		if s.italic {
+			marks += "*"
+		}
+		if s.strike {
+			marks += "~~"
+		}
+		text := strings.TrimSpace(s.text)
+		if marks == "" || text == "" {
+			sb.WriteString(s.text)
+			continue
+		}
+		// emphasis cannot start or end with spaces
+		lead := s.text[:strings.Index(s.text, text)]
+		sb.WriteString(lead)
+		sb.WriteString(marks)
+		sb.WriteString(text)
+		for i := len(marks) - 1; i >= 0; i-- {
+			sb.WriteByte(marks[i])
+		}
+		sb.WriteString(s.text[len(lead)+len(text):])
+	}
+	return sb.String()
+}
+
+// run renders the texts, breaks and images in r
+func (mw *markdownWriter) run(r *Run, br string) []markdownSpan {
+	s := markdownSpan{}
+	if rp := r.RunProperties; rp != nil {
+		s.bold = rp.Bold != nil
+		s.italic = rp.Italic != nil
+		s.strike = rp.Strike != nil && rp.Strike.Val != "0" && rp.Strike.Val != "false"
+	}
+	sb := strings.Builder{}
+	var spans []markdownSpan
+	for _, c := range r.Children {
+		switch x := c.(type) {
+		case *Text:
+			sb.WriteString(markdownEscaper.Replace(x.Text))
+		case *Tab:
+			sb.WriteByte('\t')
+		case *BarterRabbet:
+			if x.Type == "page" {
+				sb.WriteByte('\f')
+			} else {
+				sb.WriteString(br)
+			}
+		case *Drawing:
+			img := mw.image(x)
+			if img == "" {
+				continue
+			}
+			// images are not emphasized
+			s.text = sb.String()
+			sb.Reset()
+			spans = append(spans, s, markdownSpan{text: img})
+		}
+	}
+	s.text = sb.String()
+	return append(spans, s)
+}
+
+// image saves the picture in d and returns its markdown
+func (mw *markdownWriter) image(d *Drawing) string {
+	embed, alt, _ := d.picture()
+	if embed == "" {
+		return ""
+	}
+	target, err := mw.file.ReferTarget(embed)
+	if err != nil {
+		return ""
+	}
+	link := target
+	if mw.opts.MediaDir != "" {
+		name := path.Base(target)
+		m := mw.file.Media(name)
+		if m == nil {
+			return ""
+		}
+		if _, ok := mw.saved[name]; !ok && mw.err == nil {
+			mw.saved[name] = struct{}{}
+			mw.err = os.MkdirAll(mw.opts.MediaDir, 0o755)
+			if mw.err == nil {
+				mw.err = os.WriteFile(filepath.Join(mw.opts.MediaDir, name), m.Data, 0o644)
+			}
+		}
+		dir := mw.opts.MediaLink
+		if dir == "" {
+			dir = filepath.ToSlash(mw.opts.MediaDir)
+		}
+		link = path.Join(dir, name)
+	}
+	return "![" + markdownEscaper.Replace(alt) + "](" + markdownDestination(link) + ")"
+}
+
+// table renders t as a pipe table with the first row as the header
+func (mw *markdownWriter) table(t *Table) {
+	rows := make([][]string, 0, len(t.TableRows))
+	cols := 0
+	for _, tr := range t.TableRows {
+		row := make([]string, 0, len(tr.TableCells))
+		for _, tc := range tr.TableCells {
+			texts := make([]string, 0, len(tc.Items))
+			// the pipe tables cannot be nested, so the paragraphs are flattened
+			walkParagraphs(tc.Items, func(p *Paragraph) bool {
+				s := strings.ReplaceAll(mw.inline(p.Children, "<br>"), "\f", "")
+				texts = append(texts, strings.ReplaceAll(s, "|", `\|`))
+				return true
+			})
+			text := strings.Join(texts, "<br>")
+			if tc.vMergeContinued() {
+				text = "" // merged into the cell above
+			}
+			row = append(row, text)
+			for i := 1; i < tc.gridSpan(); i++ {
+				row = append(row, "")
+			}
+		}
+		if len(row) > cols {
+			cols = len(row)
+		}
+		rows = append(rows, row)
+	}
+	if cols == 0 {
+		return
+	}
+	sb := strings.Builder{}
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteByte('|')
+		for j := 0; j < cols; j++ {
+			text := ""
+			if j < len(row) {
+				text = row[j]
+			}
+			sb.WriteString(" " + text + " |")
+		}
+		if i == 0 {
+			sb.WriteString("\n|" + strings.Repeat(" --- |", cols))
+		}
+	}
+	mw.add(sb.String(), 0)
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	w := New().WithDefaultTheme()
+	_, err := w.AddHeading("Title", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := w.AddParagraph()
+	p.AddText("plain ")
+	p.AddText("bold ").Bold()
+	p.AddText("both").Bold().Italic()
+	p.AddText(" and 2*3")
+	w.AddParagraph().AddLink("google", "http://google.com")
+	w.AddParagraph().AddLink("see [1]", "http://x.com/a (b) <c>")
+	bullet, err := w.AddList(LIST_BULLET)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().ListItem(bullet, 0).AddText("one")
+	w.AddParagraph().ListItem(bullet, 1).AddText("two")
+	decimal, err := w.AddList(LIST_DECIMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().ListItem(decimal, 0).AddText("first")
+	_, err = w.AddParagraph().AddInlineDrawingFrom("testdata/fumiama.JPG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.AddParagraph().AddPageBreaks()
+	tbl := w.AddTable(2, 2)
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("a|b")
+	tbl.TableRows[0].TableCells[1].AddParagraph().AddText("h")
+	tbl.TableRows[1].TableCells[0].AddParagraph().AddText("x")
+	tbl.TableRows[1].TableCells[0].AddParagraph().AddText("y")
+
+	dir := t.TempDir()
+	var sb strings.Builder
+	err = w.WriteMarkdown(&sb, MarkdownOptions{MediaDir: filepath.Join(dir, "media"), MediaLink: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := sb.String()
+	for _, s := range []string{
+		"# Title\n\n",
+		"plain **bold** ***both*** and 2\\*3\n\n",
+		"[google](http://google.com)\n\n",
+		"[see \\[1\\]](<http://x.com/a (b) %3Cc%3E>)\n\n",
+		"- one\n    - two\n\n1. first\n\n",
+		"![",
+		"](media/image1.jpeg)\n\n" + MarkdownPageBreak + "\n\n",
+		"| a\\|b | h |\n| --- | --- |\n| x<br>y |  |\n",
+	} {
+		if !strings.Contains(md, s) {
+			t.Fatalf("expected %q in %q", s, md)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(dir, "media", "image1.jpeg")); err != nil {
+		t.Fatal(err)
+	}
+	w, err = NewFromMarkdown(strings.NewReader(md), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var links []*Hyperlink
+	walkParagraphs(w.Document.Body.Items, func(p *Paragraph) bool {
+		for _, c := range p.Children {
+			if h, ok := c.(*Hyperlink); ok {
+				links = append(links, h)
+			}
+		}
+		return true
+	})
+	if len(links) != 2 {
+		t.Fatal("expected 2 links but has", len(links))
+	}
+	h := links[1]
+	if s := h.Run.InstrText + runText(&h.Run); s != "see [1]" {
+		t.Fatalf("unexpected link text %q", s)
+	}
+	if target, _ := w.ReferTarget(h.ID); target != "http://x.com/a (b) %3Cc%3E" {
+		t.Fatalf("unexpected link target %q", target)
+	}
+}
@@ -0,0 +1,1049 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/base64"
+	"html"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the kinds of markdown blocks
+const (
+	mdParagraph = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdTable
+	mdRule
+	mdPageBreak
+)
+
+// the kinds of markdown inlines
+const (
+	mdText = iota
+	mdCodeSpan
+	mdLink
+	mdImage
+	mdBreak
+	mdDelim
+)
+
+var (
+	mdFenceRegex      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	mdATXRegex        = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*))?$`)
+	mdATXCloseRegex   = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	mdRuleRegex       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdSetextRegex     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	mdQuoteRegex      = regexp.MustCompile(`^ {0,3}> ?`)
+	mdListRegex       = regexp.MustCompile(`^( {0,3})([-+*]|[0-9]{1,9}[.)])([ \t]+|$)`)
+	mdTableDelimRegex = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdRefDefRegex     = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^ \t>]+)>?(?:[ \t]+(?:"[^"]*"|'[^']*'|\([^)]*\)))?[ \t]*$`)
+	mdAutolinkRegex   = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^ \t\n<>]*)>`)
+	mdEmailRegex      = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*)>`)
+	mdBreakTagRegex   = regexp.MustCompile(`^<br[ \t]*/?>`)
+	mdEntityRegex     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+// mdBlock is a parsed markdown block
+type mdBlock struct {
+	kind    int
+	level   int          // level of headings
+	text    string       // text of paragraphs, headings and code
+	blocks  []*mdBlock   // blocks in quotes
+	items   [][]*mdBlock // blocks of the items of lists
+	ordered bool
+	start   int
+	rows    [][]string // cells of tables, the first row is the header
+	aligns  []string   // the justification of the columns of tables
+}
+
+// mdInline is a parsed piece of inline markdown
+type mdInline struct {
+	kind     int
+	text     string
+	url      string
+	children []*mdInline // text of links
+
+	delim       byte // delimiter of emphasis
+	count       int  // number of delimiters left
+	open, close bool
+
+	bold, italic, strike bool
+}
+
+// markdownBuilder adds the parsed markdown into the file
+type markdownBuilder struct {
+	file    *Docx
+	styles  *Styles
+	dir     string
+	refs    map[string]string
+	bullet  *Num
+	decimal int // the abstractNumId of ordered lists, -1 if not added
+}
+
+// mdContext is where blocks are added
+type mdContext struct {
+	depth  int // the level of nested lists
+	indent int // the indent of paragraphs in list items
+	quote  bool
+}
+
+// NewFromMarkdown makes a new A4 document from markdown,
+// see Docx.AddMarkdown.
+func NewFromMarkdown(r io.Reader, dir string) (*Docx, error) {
+	f := New().WithDefaultTheme().WithA4Page()
+	err := f.AddMarkdown(r, dir)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// AddMarkdown appends CommonMark with GFM tables and strikethrough to the body.
+// Headings use the heading styles, code uses the styles in monospace and lists
+// use the real numbering. Local images are loaded relative to dir.
+func (f *Docx) AddMarkdown(r io.Reader, dir string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s, err := f.Styles()
+	if err != nil {
+		return err
+	}
+	mb := &markdownBuilder{file: f, styles: s, dir: dir, refs: make(map[string]string, 8), decimal: -1}
+	src := strings.ReplaceAll(strings.ReplaceAll(BytesToString(data), "\r\n", "\n"), "\r", "\n")
+	lines := strings.Split(src, "\n")
+	for i, l := range lines {
+		lines[i] = mdExpandTabs(l)
+	}
+	return mb.blocks(mb.parse(lines), mdContext{})
+}
+
+// mdExpandTabs expands the tabs in the indent of line to spaces
+func mdExpandTabs(line string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			sb.WriteByte(' ')
+		case '\t':
+			sb.WriteString(strings.Repeat(" ", 4-sb.Len()%4))
+		default:
+			sb.WriteString(line[i:])
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+func mdIsBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func mdIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// mdListItemStart returns the indent of the content of the list item
+// and its first line
+func mdListItemStart(line string, m []string) (int, string) {
+	width := len(m[1]) + len(m[2])
+	rest := line[width:]
+	if mdIsBlank(rest) {
+		return width + 1, ""
+	}
+	sp := len(m[3])
+	if sp > 4 {
+		sp = 1 // indented code in the item
+	}
+	return width + sp, rest[sp:]
+}
+
+// interrupts reports whether line starts a block that ends a paragraph
+func (mb *markdownBuilder) interrupts(line string) bool {
+	if mdFenceRegex.MatchString(line) || mdATXRegex.MatchString(line) ||
+		mdRuleRegex.MatchString(line) || mdQuoteRegex.MatchString(line) ||
+		strings.TrimSpace(line) == MarkdownPageBreak {
+		return true
+	}
+	m := mdListRegex.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	_, first := mdListItemStart(line, m)
+	if first == "" {
+		return false
+	}
+	return !strings.HasSuffix(m[2], ".") && !strings.HasSuffix(m[2], ")") || m[2][:len(m[2])-1] == "1"
+}
+
+// parse splits lines into blocks
+func (mb *markdownBuilder) parse(lines []string) []*mdBlock {
+	blocks := make([]*mdBlock, 0, 16)
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if mdIsBlank(line) {
+			i++
+			continue
+		}
+		if m := mdFenceRegex.FindStringSubmatch(line); m != nil {
+			fence, indent := m[2], len(m[1])
+			code := make([]string, 0, 16)
+			for i++; i < len(lines); i++ {
+				l := lines[i]
+				t := strings.TrimSpace(l)
+				if mdIndent(l) < 4 && len(t) >= len(fence) && strings.Trim(t, fence[:1]) == "" {
+					i++
+					break
+				}
+				if n := mdIndent(l); n < indent {
+					l = l[n:]
+				} else {
+					l = l[indent:]
+				}
+				code = append(code, l)
+			}
+			blocks = append(blocks, &mdBlock{kind: mdCode, text: strings.Join(code, "\n")})
+			continue
+		}
+		if m := mdATXRegex.FindStringSubmatch(line); m != nil {
+			text := mdATXCloseRegex.ReplaceAllString(strings.TrimSpace(m[2]), "")
+			blocks = append(blocks, &mdBlock{kind: mdHeading, level: len(m[1]), text: text})
+			i++
+			continue
+		}
+		if mdRuleRegex.MatchString(line) {
+			blocks = append(blocks, &mdBlock{kind: mdRule})
+			i++
+			continue
+		}
+		if strings.TrimSpace(line) == MarkdownPageBreak {
+			blocks = append(blocks, &mdBlock{kind: mdPageBreak})
+			i++
+			continue
+		}
+		if mdQuoteRegex.MatchString(line) {
+			quoted := make([]string, 0, 8)
+			for ; i < len(lines); i++ {
+				l := lines[i]
+				if loc := mdQuoteRegex.FindStringIndex(l); loc != nil {
+					quoted = append(quoted, l[loc[1]:])
+					continue
+				}
+				// lazy continuation of the paragraph in quote
+				if mdIsBlank(l) || mdIsBlank(quoted[len(quoted)-1]) || mb.interrupts(l) {
+					break
+				}
+				quoted = append(quoted, l)
+			}
+			blocks = append(blocks, &mdBlock{kind: mdQuote, blocks: mb.parse(quoted)})
+			continue
+		}
+		if m := mdListRegex.FindStringSubmatch(line); m != nil {
+			var b *mdBlock
+			b, i = mb.parseList(lines, i, m)
+			blocks = append(blocks, b)
+			continue
+		}
+		if mdIndent(line) >= 4 {
+			code := make([]string, 0, 16)
+			for ; i < len(lines) && (mdIsBlank(lines[i]) || mdIndent(lines[i]) >= 4); i++ {
+				if len(lines[i]) >= 4 {
+					code = append(code, lines[i][4:])
+				} else {
+					code = append(code, "")
+				}
+			}
+			for len(code) > 0 && mdIsBlank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, &mdBlock{kind: mdCode, text: strings.Join(code, "\n")})
+			continue
+		}
+		if m := mdRefDefRegex.FindStringSubmatch(line); m != nil {
+			label := mdLabel(m[1])
+			if _, ok := mb.refs[label]; !ok {
+				mb.refs[label] = m[2]
+			}
+			i++
+			continue
+		}
+		if i+1 < len(lines) && strings.Contains(line, "|") && mdTableDelimRegex.MatchString(lines[i+1]) {
+			header, delims := mdTableCells(line), mdTableCells(lines[i+1])
+			if len(header) == len(delims) {
+				b := &mdBlock{kind: mdTable, rows: [][]string{header}, aligns: make([]string, len(delims))}
+				for j, d := range delims {
+					switch {
+					case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+						b.aligns[j] = "center"
+					case strings.HasSuffix(d, ":"):
+						b.aligns[j] = "end"
+					case strings.HasPrefix(d, ":"):
+						b.aligns[j] = "start"
+					}
+				}
+				for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && !mb.interrupts(lines[i]); i++ {
+					b.rows = append(b.rows, mdTableCells(lines[i]))
+				}
+				blocks = append(blocks, b)
+				continue
+			}
+		}
+		// paragraph or setext heading
+		b := &mdBlock{kind: mdParagraph}
+		para := []string{strings.TrimLeft(line, " ")}
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if mdIsBlank(l) {
+				break
+			}
+			if m := mdSetextRegex.FindStringSubmatch(l); m != nil {
+				b.kind, b.level = mdHeading, 2
+				if m[1][0] == '=' {
+					b.level = 1
+				}
+				i++
+				break
+			}
+			if mb.interrupts(l) {
+				break
+			}
+			para = append(para, strings.TrimLeft(l, " "))
+		}
+		b.text = strings.Join(para, "\n")
+		if b.kind == mdHeading {
+			b.text = strings.TrimSpace(b.text)
+		} else {
+			b.text = strings.TrimRight(b.text, " ")
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// parseList parses the list starting at lines[i], whose first marker is m,
+// and returns the index of the next line
+func (mb *markdownBuilder) parseList(lines []string, i int, m []string) (*mdBlock, int) {
+	marker := m[2]
+	delim := marker[len(marker)-1]
+	b := &mdBlock{kind: mdList}
+	if delim == '.' || delim == ')' {
+		b.ordered = true
+		b.start, _ = strconv.Atoi(marker[:len(marker)-1])
+	}
+	for i < len(lines) {
+		m := mdListRegex.FindStringSubmatch(lines[i])
+		if m == nil || mdRuleRegex.MatchString(lines[i]) || m[2][len(m[2])-1] != delim {
+			break
+		}
+		width, first := mdListItemStart(lines[i], m)
+		item := []string{first}
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if mdIsBlank(l) {
+				item = append(item, "")
+				continue
+			}
+			if mdIndent(l) >= width {
+				item = append(item, l[width:])
+				continue
+			}
+			// lazy continuation of the paragraph in item
+			if mdIsBlank(item[len(item)-1]) || mb.interrupts(l) || mdListRegex.MatchString(l) {
+				break
+			}
+			item = append(item, strings.TrimLeft(l, " "))
+		}
+		b.items = append(b.items, mb.parse(item))
+	}
+	return b, i
+}
+
+// mdTableCells splits a row of table by unescaped pipes
+func mdTableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	cells := make([]string, 0, 8)
+	sb := strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			sb.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(sb.String()))
+}
+
+// mdLabel normalizes the label of link references
+func mdLabel(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func mdIsPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// inlines parses s into inline pieces with emphasis resolved
+func (mb *markdownBuilder) inlines(s string) []*mdInline {
+	out := make([]*mdInline, 0, 16)
+	sb := strings.Builder{}
+	flush := func() {
+		if sb.Len() > 0 {
+			out = append(out, &mdInline{kind: mdText, text: sb.String()})
+			sb.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				flush()
+				out = append(out, &mdInline{kind: mdBreak})
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && s[i+1] < utf8.RuneSelf && mdIsPunct(rune(s[i+1])) {
+				sb.WriteByte(s[i+1])
+				i += 2
+				continue
+			}
+		case '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			if j := mdCodeSpanEnd(s, i+n, n); j >= 0 {
+				flush()
+				code := strings.ReplaceAll(s[i+n:j], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+					code = code[1 : len(code)-1]
+				}
+				out = append(out, &mdInline{kind: mdCodeSpan, text: code})
+				i = j + n
+				continue
+			}
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		case '*', '_', '~':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], string(c)))
+			if c == '~' && n > 2 {
+				sb.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			before, after := ' ', ' '
+			if i > 0 {
+				before, _ = utf8.DecodeLastRuneInString(s[:i])
+			}
+			if i+n < len(s) {
+				after, _ = utf8.DecodeRuneInString(s[i+n:])
+			}
+			left := !unicode.IsSpace(after) && (!mdIsPunct(after) || unicode.IsSpace(before) || mdIsPunct(before))
+			right := !unicode.IsSpace(before) && (!mdIsPunct(before) || unicode.IsSpace(after) || mdIsPunct(after))
+			d := &mdInline{kind: mdDelim, delim: c, count: n, open: left, close: right}
+			if c == '_' {
+				d.open = left && (!right || mdIsPunct(before))
+				d.close = right && (!left || mdIsPunct(after))
+			}
+			flush()
+			out = append(out, d)
+			i += n
+			continue
+		case '!', '[':
+			img := c == '!'
+			j := i
+			if img {
+				j++
+			}
+			if j < len(s) && s[j] == '[' {
+				if label, dest, end, ok := mb.parseLink(s, j); ok {
+					flush()
+					if img {
+						out = append(out, &mdInline{kind: mdImage, text: mdPlainText(mb.inlines(label)), url: dest})
+					} else {
+						out = append(out, &mdInline{kind: mdLink, children: mb.inlines(label), url: dest})
+					}
+					i = end
+					continue
+				}
+			}
+		case '<':
+			if m := mdAutolinkRegex.FindStringSubmatch(s[i:]); m != nil {
+				flush()
+				out = append(out, &mdInline{kind: mdLink, children: []*mdInline{{kind: mdText, text: m[1]}}, url: m[1]})
+				i += len(m[0])
+				continue
+			}
+			if m := mdEmailRegex.FindStringSubmatch(s[i:]); m != nil {
+				flush()
+				out = append(out, &mdInline{kind: mdLink, children: []*mdInline{{kind: mdText, text: m[1]}}, url: "mailto:" + m[1]})
+				i += len(m[0])
+				continue
+			}
+			if m := mdBreakTagRegex.FindString(s[i:]); m != "" {
+				flush()
+				out = append(out, &mdInline{kind: mdBreak})
+				i += len(m)
+				continue
+			}
+		case '&':
+			if m := mdEntityRegex.FindString(s[i:]); m != "" {
+				sb.WriteString(html.UnescapeString(m))
+				i += len(m)
+				continue
+			}
+		case '\n':
+			text := sb.String()
+			trimmed := strings.TrimRight(text, " ")
+			sb.Reset()
+			sb.WriteString(trimmed)
+			if len(text)-len(trimmed) >= 2 {
+				flush()
+				out = append(out, &mdInline{kind: mdBreak})
+			} else {
+				sb.WriteByte(' ')
+			}
+			i++
+			continue
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	flush()
+	mdEmphasis(out)
+	return out
+}
+
+// mdCodeSpanEnd finds the closing backticks of length n from i, or returns -1
+func mdCodeSpanEnd(s string, i, n int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+		i += j
+		k := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+		if k == n {
+			return i
+		}
+		i += k
+	}
+	return -1
+}
+
+// parseLink parses the link starting with [ at s[i] into its label and
+// destination, and returns the index after it
+func (mb *markdownBuilder) parseLink(s string, i int) (label, dest string, end int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(s) {
+		return
+	}
+	label, end = s[i+1:j], j+1
+	if end < len(s) && s[end] == '(' {
+		k := end + 1
+		for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+			k++
+		}
+		if k < len(s) && s[k] == '<' {
+			e := strings.IndexByte(s[k:], '>')
+			if e < 0 {
+				return
+			}
+			dest, k = s[k+1:k+e], k+e+1
+		} else {
+			start, parens := k, 0
+			for ; k < len(s) && s[k] > ' '; k++ {
+				if s[k] == '(' {
+					parens++
+				} else if s[k] == ')' {
+					if parens == 0 {
+						break
+					}
+					parens--
+				} else if s[k] == '\\' {
+					k++
+				}
+			}
+			if k > len(s) {
+				return
+			}
+			dest = s[start:k]
+		}
+		for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+			k++
+		}
+		if k < len(s) && (s[k] == '"' || s[k] == '\'' || s[k] == '(') {
+			closing := s[k]
+			if closing == '(' {
+				closing = ')'
+			}
+			e := strings.IndexByte(s[k+1:], closing)
+			if e < 0 {
+				return
+			}
+			k += e + 2
+			for k < len(s) && (s[k] == ' ' || s[k] == '\n') {
+				k++
+			}
+		}
+		if k >= len(s) || s[k] != ')' {
+			return
+		}
+		return label, mdUnescape(dest), k + 1, true
+	}
+	ref := label
+	if end+1 < len(s) && s[end] == '[' {
+		if e := strings.IndexByte(s[end+1:], ']'); e >= 0 {
+			if r := s[end+1 : end+1+e]; r != "" {
+				ref = r
+			}
+			end += e + 2
+		}
+	}
+	dest, ok = mb.refs[mdLabel(ref)]
+	return label, dest, end, ok
+}
+
+// mdUnescape removes the backslash escapes and entities in s
+func mdUnescape(s string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && mdIsPunct(rune(s[i+1])) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return html.UnescapeString(sb.String())
+}
+
+// mdEmphasis matches the delimiters and marks the pieces between them
+func mdEmphasis(ins []*mdInline) {
+	for c := 0; c < len(ins); c++ {
+		closer := ins[c]
+		if closer.kind != mdDelim || !closer.close || closer.count == 0 {
+			continue
+		}
+		for o := c - 1; o >= 0; o-- {
+			opener := ins[o]
+			if opener.kind != mdDelim || !opener.open || opener.delim != closer.delim || opener.count == 0 {
+				continue
+			}
+			if closer.delim == '~' {
+				if opener.count != closer.count {
+					continue
+				}
+			} else if (opener.close || closer.open) && (opener.count+closer.count)%3 == 0 &&
+				(opener.count%3 != 0 || closer.count%3 != 0) {
+				continue // the rule of 3
+			}
+			n := 1
+			if opener.count >= 2 && closer.count >= 2 {
+				n = 2
+			}
+			if closer.delim == '~' {
+				n = closer.count
+			}
+			for _, x := range ins[o+1 : c] {
+				switch {
+				case closer.delim == '~':
+					x.strike = true
+				case n == 2:
+					x.bold = true
+				default:
+					x.italic = true
+				}
+				if x.kind == mdDelim {
+					x.open, x.close = false, false
+				}
+			}
+			opener.count -= n
+			closer.count -= n
+			if closer.count > 0 {
+				c-- // match the rest of closer again
+			}
+			break
+		}
+	}
+	for _, x := range ins {
+		if x.kind == mdDelim {
+			x.kind, x.text = mdText, strings.Repeat(string(x.delim), x.count)
+		}
+	}
+}
+
+// mdPlainText joins the texts of ins
+func mdPlainText(ins []*mdInline) string {
+	sb := strings.Builder{}
+	for _, x := range ins {
+		switch x.kind {
+		case mdText, mdCodeSpan, mdImage:
+			sb.WriteString(x.text)
+		case mdLink:
+			sb.WriteString(mdPlainText(x.children))
+		case mdBreak:
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
+
+// blocks adds bs into the body
+func (mb *markdownBuilder) blocks(bs []*mdBlock, ctx mdContext) error {
+	for _, b := range bs {
+		var err error
+		switch b.kind {
+		case mdParagraph:
+			err = mb.addInlines(mb.newParagraph(ctx), mb.inlines(b.text))
+		case mdHeading:
+			p := mb.newParagraph(ctx).Style(mb.styles.headingStyle(b.level))
+			err = mb.addInlines(p, mb.inlines(b.text))
+		case mdCode:
+			p := mb.newParagraph(ctx).Style(mb.styles.codeStyle())
+			if b.text != "" {
+				p.Children = append(p.Children, &Run{RunProperties: &RunProperties{}, Children: textChildren(b.text)})
+			}
+		case mdQuote:
+			q := ctx
+			q.quote = true
+			err = mb.blocks(b.blocks, q)
+		case mdList:
+			err = mb.list(b, ctx)
+		case mdTable:
+			err = mb.table(b)
+		case mdRule:
+			w := mb.file.contentWidth()
+			mb.newParagraph(ctx).Justification("center").AddInlineShape(w, 0, "Line", "auto", "line", &ALine{
+				W:         9525,
+				SolidFill: &ASolidFill{SrgbClr: &ASrgbClr{Val: "A0A0A0"}},
+			})
+		case mdPageBreak:
+			mb.newParagraph(ctx).AddPageBreaks()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mb *markdownBuilder) newParagraph(ctx mdContext) *Paragraph {
+	p := mb.file.AddParagraph()
+	if ctx.quote {
+		p.Style(mb.styles.quoteStyle())
+	}
+	if ctx.indent > 0 {
+		if p.Properties == nil {
+			p.Properties = &ParagraphProperties{}
+		}
+		p.Properties.Ind = &Ind{Left: ctx.indent}
+	}
+	return p
+}
+
+// list adds the items of b in the real numbering
+func (mb *markdownBuilder) list(b *mdBlock, ctx mdContext) error {
+	level := ctx.depth
+	if level > 8 {
+		level = 8
+	}
+	num, err := mb.num(b, level)
+	if err != nil {
+		return err
+	}
+	first := ctx
+	first.indent = 0
+	inner := ctx
+	inner.depth, inner.indent = ctx.depth+1, 420*(level+1)
+	for _, item := range b.items {
+		p := mb.newParagraph(first)
+		if len(item) > 0 && (item[0].kind == mdParagraph || item[0].kind == mdHeading) {
+			err = mb.addInlines(p, mb.inlines(item[0].text))
+			if err != nil {
+				return err
+			}
+			item = item[1:]
+		}
+		p.ListItem(num, level)
+		err = mb.blocks(item, inner)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// num returns the list of b, all bullets share the same one
+// while every ordered list starts from its own number
+func (mb *markdownBuilder) num(b *mdBlock, level int) (*Num, error) {
+	if !b.ordered {
+		if mb.bullet == nil {
+			num, err := mb.file.AddList(LIST_BULLET)
+			if err != nil {
+				return nil, err
+			}
+			mb.bullet = num
+		}
+		return mb.bullet, nil
+	}
+	var num *Num
+	if mb.decimal < 0 {
+		n, err := mb.file.AddList(LIST_DECIMAL)
+		if err != nil {
+			return nil, err
+		}
+		num, mb.decimal = n, n.AbstractNumID.Val
+	} else {
+		n, err := mb.file.Numbering()
+		if err != nil {
+			return nil, err
+		}
+		num = n.AddNum(mb.decimal)
+	}
+	num.LvlOverrides = []*LvlOverride{{Ilvl: level, StartOverride: &StartOverride{Val: b.start}}}
+	return num, nil
+}
+
+// table adds b as a table with a bold header
+func (mb *markdownBuilder) table(b *mdBlock) error {
+	cols := len(b.aligns)
+	tbl := mb.file.AddTable(len(b.rows), cols)
+	for i, row := range b.rows {
+		for j, c := range tbl.TableRows[i].TableCells {
+			p := c.AddParagraph()
+			if b.aligns[j] != "" {
+				p.Justification(b.aligns[j])
+			}
+			if j >= len(row) {
+				continue
+			}
+			ins := mb.inlines(row[j])
+			if i == 0 {
+				for _, x := range ins {
+					x.bold = true
+				}
+			}
+			err := mb.addInlines(p, ins)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addInlines adds runs, links and images of ins into p
+func (mb *markdownBuilder) addInlines(p *Paragraph, ins []*mdInline) error {
+	for i := 0; i < len(ins); i++ {
+		x := ins[i]
+		switch x.kind {
+		case mdText:
+			text := x.text
+			for i+1 < len(ins) && ins[i+1].kind == mdText && ins[i+1].bold == x.bold &&
+				ins[i+1].italic == x.italic && ins[i+1].strike == x.strike {
+				i++
+				text += ins[i].text
+			}
+			if text == "" {
+				continue
+			}
+			r := &Run{RunProperties: &RunProperties{}, Children: textChildren(text)}
+			p.Children = append(p.Children, x.emphasize(r))
+		case mdCodeSpan:
+			r := &Run{RunProperties: &RunProperties{}, Children: []interface{}{newText(x.text)}}
+			p.Children = append(p.Children, x.emphasize(r.Style(mb.styles.verbatimStyle())))
+		case mdBreak:
+			p.Children = append(p.Children, &Run{RunProperties: &RunProperties{}, Children: []interface{}{&BarterRabbet{}}})
+		case mdLink:
+			text := mdPlainText(x.children)
+			if strings.HasPrefix(x.url, "#") {
+				h := &Hyperlink{
+					Anchor: x.url[1:],
+					Run: Run{
+						RunProperties: &RunProperties{RunStyle: &RunStyle{Val: HYPERLINK_STYLE}},
+						InstrText:     text,
+					},
+				}
+				x.emphasize(&h.Run)
+				p.Children = append(p.Children, h)
+				continue
+			}
+			x.emphasize(&p.AddLink(text, x.url).Run)
+		case mdImage:
+			err := mb.image(p, x)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// emphasize applies the emphasis of x to r
+func (x *mdInline) emphasize(r *Run) *Run {
+	if x.bold {
+		r.Bold()
+	}
+	if x.italic {
+		r.Italic()
+	}
+	if x.strike {
+		r.Strike()
+	}
+	return r
+}
+
+// image adds the local or data uri image of x into p, no wider than the page,
+// or a link to it if it is remote
+func (mb *markdownBuilder) image(p *Paragraph, x *mdInline) error {
+	var r *Run
+	switch {
+	case strings.HasPrefix(x.url, "data:"):
+		i := strings.Index(x.url, ";base64,")
+		if i < 0 {
+			return nil
+		}
+		data, err := base64.StdEncoding.DecodeString(x.url[i+8:])
+		if err != nil {
+			return err
+		}
+		r, err = p.AddInlineDrawing(data)
+		if err != nil {
+			return err
+		}
+	case strings.Contains(x.url, "://"):
+		text := x.text
+		if text == "" {
+			text = x.url
+		}
+		p.AddLink(text, x.url)
+		return nil
+	default:
+		name, err := url.PathUnescape(x.url)
+		if err != nil {
+			name = x.url
+		}
+		name = filepath.FromSlash(name)
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(mb.dir, name)
+		}
+		r, err = p.AddInlineDrawingFrom(name)
+		if err != nil {
+			return err
+		}
+	}
+	d := r.Children[0].(*Drawing).Inline
+	d.DocPr.Descr = x.text
+	if w := mb.file.contentWidth(); d.Extent.CX > w {
+		d.Size(w, d.Extent.CY*w/d.Extent.CX)
+	}
+	return nil
+}
+
+// contentWidth is the width between the margins of the last section in EMU
+func (f *Docx) contentWidth() int64 {
+	w, left, right := 11906, 1800, 1800 // A4 in Word's default
+	if sect := f.lastSectPr(); sect != nil {
+		if sect.PgSz != nil {
+			if v, err := strconv.Atoi(sect.PgSz.W.Value); err == nil {
+				w = v
+			}
+		}
+		if sect.PgMar != nil {
+			left, right = sect.PgMar.Left, sect.PgMar.Right
+		}
+	}
+	return int64(w-left-right) * 635
+}
+
+// codeStyle returns the id of the paragraph style of code blocks,
+// which will be added if not exist
+func (s *Styles) codeStyle() string {
+	if sd := s.StyleByName("Source Code"); sd != nil {
+		return sd.StyleID
+	}
+	sd := s.AddStyle(STYLE_TYPE_PARAGRAPH, "SourceCode", "Source Code").WithQFormat().
+		Font("Consolas", "Consolas", "default").Size("20")
+	if d := s.Default(STYLE_TYPE_PARAGRAPH); d != nil {
+		sd.WithBasedOn(d.StyleID)
+	}
+	sd.paragraphProperties().Shade = &Shade{Val: "clear", Color: "auto", Fill: "F2F2F2"}
+	return sd.StyleID
+}
+
+// verbatimStyle returns the id of the character style of inline code,
+// which will be added if not exist
+func (s *Styles) verbatimStyle() string {
+	if sd := s.StyleByName("Verbatim Char"); sd != nil {
+		return sd.StyleID
+	}
+	sd := s.AddStyle(STYLE_TYPE_CHARACTER, "VerbatimChar", "Verbatim Char").WithQFormat().
+		Font("Consolas", "Consolas", "default")
+	sd.runProperties().Shade = &Shade{Val: "clear", Color: "auto", Fill: "F2F2F2"}
+	return sd.StyleID
+}
+
+// quoteStyle returns the id of the paragraph style of block quotes,
+// which will be added if not exist
+func (s *Styles) quoteStyle() string {
+	if sd := s.StyleByName("Quote"); sd != nil {
+		return sd.StyleID
+	}
+	sd := s.AddStyle(STYLE_TYPE_PARAGRAPH, "Quote", "Quote").WithQFormat().Italic().Color("595959")
+	sd.CustomStyle = ""
+	if d := s.Default(STYLE_TYPE_PARAGRAPH); d != nil {
+		sd.WithBasedOn(d.StyleID)
+	}
+	sd.paragraphProperties().Ind = &Ind{Left: 420}
+	return sd.StyleID
+}
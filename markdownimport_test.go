@@ -0,0 +1,147 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownImportSource = `Title
+=====
+
+## Sub *title* ##
+
+Some **bold**, *italic*, ***both***, ~~gone~~ and ` + "`code`" + `
+with a [link](http://google.com "Google") and [ref][g].  
+Next line.
+
+[g]: http://example.com
+
+- one
+- two
+  - nested
+
+3. third
+4. fourth
+
+> quoted
+
+` + "```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```" + `
+
+| a | b |
+|:--|--:|
+| 1 | 2 \| 3 |
+
+![logo](testdata/fumiama.JPG)
+
+***
+`
+
+func TestAddMarkdown(t *testing.T) {
+	w, err := NewFromMarkdown(strings.NewReader(markdownImportSource), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ps []*Paragraph
+	var tbl *Table
+	for _, it := range w.Document.Body.Items {
+		switch o := it.(type) {
+		case *Paragraph:
+			ps = append(ps, o)
+		case *Table:
+			tbl = o
+		}
+	}
+	if len(ps) != 12 {
+		t.Fatal("expected 12 paragraphs but has", len(ps))
+	}
+	if w.HeadingLevel(ps[0]) != 1 || ps[0].String() != "Title" {
+		t.Fatal("expected heading 1 Title but has", ps[0].String())
+	}
+	if w.HeadingLevel(ps[1]) != 2 || ps[1].String() != "Sub title" {
+		t.Fatal("expected heading 2 Sub title but has", ps[1].String())
+	}
+	p := ps[2]
+	var bold, italic, strike, code, links, breaks int
+	for _, c := range p.Children {
+		switch o := c.(type) {
+		case *Run:
+			if o.RunProperties.Bold != nil {
+				bold++
+			}
+			if o.RunProperties.Italic != nil {
+				italic++
+			}
+			if o.RunProperties.Strike != nil {
+				strike++
+			}
+			if o.RunProperties.RunStyle != nil && o.RunProperties.RunStyle.Val == "VerbatimChar" {
+				code++
+			}
+			for _, x := range o.Children {
+				if _, ok := x.(*BarterRabbet); ok {
+					breaks++
+				}
+			}
+		case *Hyperlink:
+			links++
+		}
+	}
+	if bold != 2 || italic != 2 || strike != 1 || code != 1 || links != 2 || breaks != 1 {
+		t.Fatal("unexpected inlines", bold, italic, strike, code, links, breaks, p.String())
+	}
+	n, err := w.Numbering()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, lv := range []int{0, 0, 1, 0, 0} {
+		numpr := ps[3+i].Properties.NumPr
+		if numpr == nil || numpr.Ilvl.Val != lv {
+			t.Fatal("expected list item of level", lv, "at", i)
+		}
+		ordered := n.Num(numpr.NumID.Val).Level(lv).NumFmt.Val != LIST_BULLET
+		if ordered != (i >= 3) {
+			t.Fatal("unexpected list kind at", i)
+		}
+	}
+	num := n.Num(ps[6].Properties.NumPr.NumID.Val)
+	if len(num.LvlOverrides) != 1 || num.LvlOverrides[0].StartOverride.Val != 3 {
+		t.Fatal("expected ordered list starting from 3")
+	}
+	if ps[8].Properties.Style.Val != "Quote" || ps[8].String() != "quoted" {
+		t.Fatal("unexpected quote", ps[8].String())
+	}
+	if ps[9].Properties.Style.Val != "SourceCode" || len(ps[9].Children[0].(*Run).Children) != 5 {
+		t.Fatal("unexpected code", ps[9].String())
+	}
+	if tbl == nil || len(tbl.TableRows) != 2 || tbl.TableRows[1].TableCells[1].Paragraphs()[0].String() != "2 | 3" ||
+		tbl.TableRows[1].TableCells[1].Paragraphs()[0].Properties.Justification.Val != "end" {
+		t.Fatal("unexpected table", tbl)
+	}
+	d := ps[10].Children[0].(*Run).Children[0].(*Drawing)
+	if d.Inline.DocPr.Descr != "logo" || d.Inline.Extent.CX > w.contentWidth() {
+		t.Fatal("unexpected image", d.Inline.DocPr.Descr, d.Inline.Extent.CX)
+	}
+	if ps[11].Children[0].(*Run).Children[0].(*Drawing).Inline.Graphic.GraphicData.Shape == nil {
+		t.Fatal("expected a line of thematic break")
+	}
+}
@@ -25,38 +25,134 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // pack receives a zip file writer (word documents are a zip with multiple xml inside)
 // and writes the relevant files. Some of them come from the empty_constants file,
 // others from the actual in-memory structure
-func (f *Docx) pack(zipWriter *zip.Writer) (err error) {
-	files := make(map[string]io.Reader, 64)
+func (f *Docx) pack(zipWriter *zip.Writer) error {
+	files, err := f.packFiles()
+	if err != nil {
+		return err
+	}
+	return writeFiles(zipWriter, files)
+}
 
-	if f.template != "" {
-		for _, name := range f.tmpfslst {
-			files[name], err = f.tmplfs.Open("xml/" + f.template + "/" + name)
-			if err != nil {
-				return
-			}
-		}
-	} else {
-		for _, name := range f.tmpfslst {
-			files[name], err = f.tmplfs.Open(name)
-			if err != nil {
-				return
-			}
+// packFiles collects all files of the package by their paths
+func (f *Docx) packFiles() (files map[string]io.Reader, err error) {
+	files = make(map[string]io.Reader, 64)
+
+	for _, name := range f.tmpfslst {
+		files[name], err = f.openTemplateFile(name)
+		if err != nil {
+			return
 		}
 	}
 
 	files["word/_rels/document.xml.rels"] = marshaller{data: &f.docRelation}
 	files["word/document.xml"] = marshaller{data: &f.Document}
 
+	ct, err := f.ContentTypes()
+	if err != nil {
+		return
+	}
+	ct = ct.clone()
+	if c, ok := files[CONTENT_TYPES_NAME].(io.Closer); ok {
+		_ = c.Close()
+	}
+	for _, h := range f.headers {
+		err = f.packPart(files, h.name, h)
+		if err != nil {
+			return
+		}
+		ct.setOverride("word/"+h.name, CONTENT_TYPE_HEADER)
+	}
+	for _, ft := range f.footers {
+		err = f.packPart(files, ft.name, ft)
+		if err != nil {
+			return
+		}
+		ct.setOverride("word/"+ft.name, CONTENT_TYPE_FOOTER)
+	}
+	if f.footnotes != nil {
+		err = f.packPart(files, f.footnotes.name, f.footnotes)
+		if err != nil {
+			return
+		}
+		ct.setOverride("word/"+f.footnotes.name, CONTENT_TYPE_FOOTNOTES)
+	}
+	if f.endnotes != nil {
+		err = f.packPart(files, f.endnotes.name, f.endnotes)
+		if err != nil {
+			return
+		}
+		ct.setOverride("word/"+f.endnotes.name, CONTENT_TYPE_ENDNOTES)
+	}
+	if f.comments != nil {
+		if f.comments.needEx() {
+			if f.comments.exname == "" {
+				f.comments.exname = "commentsExtended.xml"
+				f.addPartRelation(REL_COMMENTS_EXTENDED, f.comments.exname)
+			}
+			files["word/"+f.comments.exname] = marshaller{data: f.comments.makeEx()}
+			ct.setOverride("word/"+f.comments.exname, CONTENT_TYPE_COMMENTS_EXTENDED)
+		}
+		err = f.packPart(files, f.comments.name, f.comments)
+		if err != nil {
+			return
+		}
+		ct.setOverride("word/"+f.comments.name, CONTENT_TYPE_COMMENTS)
+	}
+	if f.styles != nil {
+		if c, ok := files["word/styles.xml"].(io.Closer); ok {
+			_ = c.Close()
+		}
+		files["word/styles.xml"] = marshaller{data: f.styles}
+		ct.setOverride("word/styles.xml", CONTENT_TYPE_STYLES)
+	}
+	if f.numbering != nil {
+		name := "word/" + f.numberingTarget(true)
+		if c, ok := files[name].(io.Closer); ok {
+			_ = c.Close()
+		}
+		files[name] = marshaller{data: f.numbering}
+		ct.setOverride(name, CONTENT_TYPE_NUMBERING)
+	}
+	if f.coreProps != nil || f.appProps != nil || f.customProps != nil {
+		var rels *Relationships
+		rels, err = readRelationships(files["_rels/.rels"])
+		if err != nil {
+			return
+		}
+		if f.coreProps != nil {
+			packPackagePart(files, rels, REL_CORE_PROPERTIES, f.coreProps.name, f.coreProps)
+			ct.setOverride(f.coreProps.name, CONTENT_TYPE_CORE_PROPERTIES)
+		}
+		if f.appProps != nil {
+			packPackagePart(files, rels, REL_EXTENDED_PROPERTIES, f.appProps.name, f.appProps)
+			ct.setOverride(f.appProps.name, CONTENT_TYPE_EXTENDED_PROPERTIES)
+		}
+		if f.customProps != nil {
+			packPackagePart(files, rels, REL_CUSTOM_PROPERTIES, f.customProps.name, f.customProps)
+			ct.setOverride(f.customProps.name, CONTENT_TYPE_CUSTOM_PROPERTIES)
+		}
+		files["_rels/.rels"] = marshaller{data: rels}
+	}
 	for _, m := range f.media {
 		files[m.String()] = bytes.NewReader(m.Data)
 	}
 
+	ct.regenerate(files)
+	files[CONTENT_TYPES_NAME] = marshaller{data: ct}
+	return
+}
+
+// writeFiles writes files into the zip
+func writeFiles(zipWriter *zip.Writer, files map[string]io.Reader) error {
 	for path, r := range files {
 		w, err := zipWriter.Create(path)
 		if err != nil {
@@ -68,8 +164,134 @@ func (f *Docx) pack(zipWriter *zip.Writer) (err error) {
 			return err
 		}
 	}
+	return nil
+}
 
-	return
+// openTemplateFile opens the file of name in the template or the parsed file
+func (f *Docx) openTemplateFile(name string) (fs.File, error) {
+	if f.template != "" {
+		return f.tmplfs.Open("xml/" + f.template + "/" + name)
+	}
+	return f.tmplfs.Open(name)
+}
+
+// readContentTypes decodes [Content_Types].xml from r,
+// or makes a new one if r is nil
+func readContentTypes(r io.Reader) (*ContentTypes, error) {
+	if r == nil {
+		return newContentTypes(), nil
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	ct := &ContentTypes{}
+	err := xml.NewDecoder(r).Decode(ct)
+	if err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// readRelationships decodes _rels/.rels from r,
+// or makes a new one referring to word/document.xml if r is nil
+func readRelationships(r io.Reader) (*Relationships, error) {
+	if r == nil {
+		return &Relationships{
+			Xmlns: XMLNS_REL,
+			Relationship: []Relationship{
+				{ID: "rId1", Type: REL_OFFICE_DOCUMENT, Target: "word/document.xml"},
+			},
+		}, nil
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	rels := &Relationships{}
+	err := xml.NewDecoder(r).Decode(rels)
+	if err != nil {
+		return nil, err
+	}
+	rels.Xmlns = XMLNS_REL
+	return rels, nil
+}
+
+// packPackagePart marshals v into files as name and
+// adds the package relationship of typ if not exist
+func packPackagePart(files map[string]io.Reader, rels *Relationships, typ, name string, v interface{}) {
+	if c, ok := files[name].(io.Closer); ok {
+		_ = c.Close()
+	}
+	files[name] = marshaller{data: v}
+	maxid := 0
+	for _, r := range rels.Relationship {
+		if r.Type == typ {
+			return
+		}
+		if strings.HasPrefix(r.ID, "rId") {
+			id, err := strconv.Atoi(r.ID[3:])
+			if err == nil && id > maxid {
+				maxid = id
+			}
+		}
+	}
+	rels.Relationship = append(rels.Relationship, Relationship{
+		ID: "rId" + strconv.Itoa(maxid+1), Type: typ, Target: name,
+	})
+}
+
+// packPart marshals a part other than document.xml into files, along with
+// its relationships. The links and images added to the paragraphs of the part
+// are related in the document relationships, so they are copied into the part.
+func (f *Docx) packPart(files map[string]io.Reader, name string, v interface{}) error {
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	_, err := marshaller{data: v}.WriteTo(buf)
+	if err != nil {
+		return err
+	}
+	files["word/"+name] = buf
+	rels := f.partRels[name]
+	if rels == nil {
+		rels = &Relationships{Xmlns: XMLNS_REL}
+	}
+	has := make(map[string]struct{}, len(rels.Relationship))
+	for _, r := range rels.Relationship {
+		has[r.ID] = struct{}{}
+	}
+	d := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, a := range se.Attr {
+			if _, ok := has[a.Value]; ok || a.Name.Space != XMLNS_R {
+				continue
+			}
+			for _, r := range f.docRelation.Relationship {
+				if r.ID == a.Value {
+					rels.Relationship = append(rels.Relationship, r)
+					has[r.ID] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+	if len(rels.Relationship) == 0 {
+		return nil
+	}
+	if f.partRels == nil {
+		f.partRels = make(map[string]*Relationships, 8)
+	}
+	f.partRels[name] = rels
+	files["word/_rels/"+name+".rels"] = marshaller{data: rels}
+	return nil
 }
 
 type marshaller struct {
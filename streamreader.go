@@ -0,0 +1,144 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// ErrDocumentNotFound there is no word/document.xml in the file
+var ErrDocumentNotFound = errors.New("word/document.xml not found")
+
+// BodyReader reads the items of the body in word/document.xml one by one
+// without loading the whole document, so that huge files can be scanned
+// in a bounded memory.
+//
+// Only the document relationships are loaded into File, and the media,
+// styles, headers and others are not available.
+type BodyReader struct {
+	File *Docx
+
+	rc   io.ReadCloser
+	body Body
+	d    *xml.Decoder
+	eof  bool
+}
+
+// NewBodyReader opens word/document.xml of the docx in reader
+// and seeks to the start of the body
+func NewBodyReader(reader io.ReaderAt, size int64) (*BodyReader, error) {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+	f := &Docx{
+		mediaNameIdx: make(map[string]int, 64),
+		slowIDs:      make(map[string]uintptr, 64),
+		tmplfs:       zipReader,
+	}
+	var doc *zip.File
+	for _, zf := range zipReader.File {
+		switch zf.Name {
+		case "word/_rels/document.xml.rels":
+			err = f.parseDocRelation(zf)
+			if err != nil {
+				return nil, err
+			}
+		case "word/document.xml":
+			doc = zf
+		}
+	}
+	if doc == nil {
+		return nil, ErrDocumentNotFound
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return nil, err
+	}
+	br := &BodyReader{File: f, rc: rc, body: Body{file: f}, d: newPartDecoder(rc)}
+	for {
+		t, err := br.d.Token()
+		if err == io.EOF {
+			br.eof = true
+			return br, nil
+		}
+		if err != nil {
+			_ = rc.Close()
+			return nil, err
+		}
+		if tt, ok := t.(xml.StartElement); ok && tt.Name.Local == "body" {
+			return br, nil
+		}
+	}
+}
+
+// Next decodes the next body item like *Paragraph, *Table, *SectPr or *RawXML,
+// and returns io.EOF after the last one
+func (br *BodyReader) Next() (interface{}, error) {
+	for !br.eof {
+		t, err := br.d.Token()
+		if err == io.EOF {
+			br.eof = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			return br.body.decodeItem(br.d, &tt)
+		case xml.EndElement:
+			br.eof = true // end of body
+		}
+	}
+	return nil, io.EOF
+}
+
+// Close closes word/document.xml
+func (br *BodyReader) Close() error {
+	br.eof = true
+	return br.rc.Close()
+}
+
+// Walk calls fn on each body item of the docx in reader one by one
+// until fn returns false or all items are visited
+func Walk(reader io.ReaderAt, size int64, fn func(item interface{}) bool) error {
+	br, err := NewBodyReader(reader, size)
+	if err != nil {
+		return err
+	}
+	defer br.Close()
+	for {
+		item, err := br.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !fn(item) {
+			return nil
+		}
+	}
+}
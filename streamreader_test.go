@@ -0,0 +1,89 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBodyReader(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddText("first")
+	w.AddTable(2, 2).TableRows[1].TableCells[1].AddParagraph().AddText("cell")
+	w.AddParagraph().AddLink("link", "https://example.com/")
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	br, err := NewBodyReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := make([]interface{}, 0, 4)
+	for {
+		item, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, item)
+	}
+	err = br.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 {
+		t.Fatal("expected 4 items but has", len(items))
+	}
+	if items[0].(*Paragraph).String() != "first" {
+		t.Fatal("unexpected paragraph", items[0])
+	}
+	if items[1].(*Table).TableRows[1].TableCells[1].Paragraphs()[0].String() != "cell" {
+		t.Fatal("unexpected table", items[1])
+	}
+	link := items[2].(*Paragraph).Children[0].(*Hyperlink)
+	target, err := br.File.ReferTarget(link.ID)
+	if err != nil || target != "https://example.com/" {
+		t.Fatal("expected the link target but has", target, err)
+	}
+	if _, ok := items[3].(*SectPr); !ok {
+		t.Fatal("expected sectPr but has", items[3])
+	}
+
+	n := 0
+	err = Walk(bytes.NewReader(buf.Bytes()), int64(buf.Len()), func(item interface{}) bool {
+		n++
+		_, ok := item.(*Table)
+		return !ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatal("expected to stop at the table but visited", n)
+	}
+}
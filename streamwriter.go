@@ -0,0 +1,241 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+// ErrStreamClosed the stream writer has been closed
+var ErrStreamClosed = errors.New("stream writer closed")
+
+// StreamWriter writes the body items into word/document.xml as soon as
+// the next one is added, so that the memory usage does not grow with the body.
+// The media, relationships and other parts are written on Close.
+//
+// Items already written cannot be changed anymore, so features that
+// look up the body like AddComment do not work on them.
+type StreamWriter struct {
+	file *Docx
+	zw   *zip.Writer
+	w    io.Writer
+	enc  *xml.Encoder
+	tail []byte // tail closes w:body and w:document
+
+	pending interface{} // pending is the last added item that is not written
+	sect    *SectPr     // sect is the last section, written before the end of body
+	table   *Table      // table is the one whose rows are being written
+	row     *WTableRow  // row is the last added row of table
+
+	err error
+}
+
+// NewStreamWriter starts to write the file into w. The items already
+// in the body are written first and the following ones should be added
+// through the stream writer instead of the body.
+func (f *Docx) NewStreamWriter(w io.Writer) (*StreamWriter, error) {
+	sw := &StreamWriter{file: f, zw: zip.NewWriter(w)}
+	doc := f.Document
+	doc.Body = Body{file: f}
+	var buf bytes.Buffer
+	_, err := marshaller{data: &doc}.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	i := bytes.Index(buf.Bytes(), []byte("</w:body>"))
+	if i < 0 {
+		return nil, errors.New("unexpected document: " + buf.String())
+	}
+	sw.tail = append([]byte(nil), buf.Bytes()[i:]...)
+	sw.w, err = sw.zw.Create("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = sw.w.Write(buf.Bytes()[:i])
+	if err != nil {
+		return nil, err
+	}
+	sw.enc = xml.NewEncoder(sw.w)
+	for _, item := range f.Document.Body.Items {
+		sw.AddItem(item)
+	}
+	return sw, sw.err
+}
+
+// AddItem adds an item like *Paragraph or *Table made elsewhere
+func (sw *StreamWriter) AddItem(item interface{}) {
+	sw.writePending()
+	sw.pending = item
+}
+
+// AddParagraph adds a new paragraph
+func (sw *StreamWriter) AddParagraph() *Paragraph {
+	p := &Paragraph{
+		Children: make([]interface{}, 0, 64),
+		file:     sw.file,
+	}
+	sw.AddItem(p)
+	return p
+}
+
+// AddTable adds a new table by col*row, and
+// more rows can be streamed by AddRow
+func (sw *StreamWriter) AddTable(row int, col int) *Table {
+	tbl := sw.file.newTable(row, col)
+	sw.AddItem(tbl)
+	return tbl
+}
+
+// AddTableTwips adds a new table by height and width, and
+// more rows can be streamed by AddRow
+//
+// unit: twips (1/20 point)
+func (sw *StreamWriter) AddTableTwips(rowHeights []int64, colWidths []int64) *Table {
+	tbl := sw.file.newTableTwips(rowHeights, colWidths)
+	sw.AddItem(tbl)
+	return tbl
+}
+
+// AddRow adds a new row with the cell widths of the previous one to the
+// last added table, writing out the rows before. It returns nil if the
+// last added item is not a table.
+func (sw *StreamWriter) AddRow() *WTableRow {
+	if sw.table == nil {
+		tbl, ok := sw.pending.(*Table)
+		if !ok {
+			return nil
+		}
+		sw.pending = nil
+		sw.startTable(tbl)
+	}
+	prev := sw.row
+	if prev == nil && len(sw.table.TableRows) > 0 {
+		prev = sw.table.TableRows[len(sw.table.TableRows)-1]
+	}
+	sw.writeRows()
+	sw.row = sw.table.newRowLike(prev)
+	return sw.row
+}
+
+// Flush writes all the items added into the underlying writer,
+// and the last item cannot be changed anymore.
+func (sw *StreamWriter) Flush() error {
+	sw.writePending()
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.zw.Flush()
+}
+
+// Close writes the rest of the document and all other files of the package.
+// It does not close the underlying writer.
+func (sw *StreamWriter) Close() error {
+	sw.writePending()
+	if sw.sect != nil {
+		sw.encode(sw.sect)
+		sw.sect = nil
+	}
+	if sw.err != nil {
+		return sw.err
+	}
+	sw.err = ErrStreamClosed
+	_, err := sw.w.Write(sw.tail)
+	if err != nil {
+		return err
+	}
+	files, err := sw.file.packFiles()
+	if err != nil {
+		return err
+	}
+	delete(files, "word/document.xml") // already written
+	err = writeFiles(sw.zw, files)
+	if err != nil {
+		return err
+	}
+	return sw.zw.Close()
+}
+
+// encode writes v into document.xml if no error happened before
+func (sw *StreamWriter) encode(v interface{}) {
+	if sw.err != nil {
+		return
+	}
+	sw.err = sw.enc.Encode(v)
+}
+
+// writePending writes the pending item or finishes the table being streamed
+func (sw *StreamWriter) writePending() {
+	if sw.table != nil {
+		sw.writeRows()
+		if sw.err == nil {
+			sw.err = sw.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "w:tbl"}})
+		}
+		if sw.err == nil {
+			sw.err = sw.enc.Flush()
+		}
+		sw.table = nil
+	}
+	if sw.pending == nil {
+		return
+	}
+	if sect, ok := sw.pending.(*SectPr); ok {
+		// only the last section is written at the end of body
+		if sw.sect != nil {
+			sw.encode(sw.sect)
+		}
+		sw.sect = sect
+	} else {
+		sw.encode(sw.pending)
+	}
+	sw.pending = nil
+}
+
+// startTable writes the table except its rows
+func (sw *StreamWriter) startTable(tbl *Table) {
+	sw.table = tbl
+	sw.row = nil
+	if sw.err != nil {
+		return
+	}
+	sw.err = sw.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "w:tbl"}})
+	if tbl.TableProperties != nil {
+		sw.encode(tbl.TableProperties)
+	}
+	if tbl.TableGrid != nil {
+		sw.encode(tbl.TableGrid)
+	}
+}
+
+// writeRows writes the rows in the table being streamed and drops them
+func (sw *StreamWriter) writeRows() {
+	for _, tr := range sw.table.TableRows {
+		sw.encode(tr)
+	}
+	sw.table.TableRows = nil
+	if sw.row != nil {
+		sw.encode(sw.row)
+		sw.row = nil
+	}
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestStreamWriter(t *testing.T) {
+	w := New().WithDefaultTheme().WithA4Page()
+	w.AddParagraph().AddText("before")
+	var buf bytes.Buffer
+	sw, err := w.NewStreamWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := sw.AddParagraph()
+	p.AddText("title")
+	_, err = p.AddInlineDrawingFrom("testdata/fumiamayoko.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sw.AddRow() != nil {
+		t.Fatal("expected no row after a paragraph")
+	}
+	sw.AddTableTwips([]int64{0}, []int64{1000, 2000}).TableRows[0].TableCells[0].AddParagraph().AddText("head")
+	for i := 0; i < 1000; i++ {
+		tr := sw.AddRow()
+		tr.TableCells[0].AddParagraph().AddText(strconv.Itoa(i))
+		tr.TableCells[1].AddParagraph().AddText("row")
+	}
+	err = sw.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw.AddParagraph().AddText("after")
+	err = sw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw.AddParagraph()
+	if sw.Close() != ErrStreamClosed {
+		t.Fatal("expected ErrStreamClosed")
+	}
+
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := w.Document.Body.Items
+	if len(items) != 5 {
+		t.Fatal("expected 5 items but has", len(items))
+	}
+	if items[0].(*Paragraph).String() != "before" || items[3].(*Paragraph).String() != "after" {
+		t.Fatal("unexpected paragraphs", items[0], items[3])
+	}
+	if _, ok := items[4].(*SectPr); !ok {
+		t.Fatal("expected sectPr at the end but has", items[4])
+	}
+	tbl := items[2].(*Table)
+	if len(tbl.TableRows) != 1001 {
+		t.Fatal("expected 1001 rows but has", len(tbl.TableRows))
+	}
+	if len(tbl.TableGrid.GridCols) != 2 || tbl.TableRows[1000].TableCells[1].TableCellProperties.TableCellWidth.W != 2000 {
+		t.Fatal("expected the cell widths to be kept")
+	}
+	if tbl.TableRows[0].TableCells[0].Paragraphs()[0].String() != "head" || tbl.TableRows[1000].TableCells[0].Paragraphs()[0].String() != "999" {
+		t.Fatal("unexpected rows")
+	}
+	if len(w.media) != 1 {
+		t.Fatal("expected 1 media but has", len(w.media))
+	}
+}
@@ -0,0 +1,60 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import "encoding/xml"
+
+// BookmarkStart <w:bookmarkStart> starts the bookmark of Name,
+// which ends at the BookmarkEnd of the same ID
+type BookmarkStart struct {
+	XMLName  xml.Name `xml:"w:bookmarkStart"`
+	ID       int      `xml:"w:id,attr"`
+	Name     string   `xml:"w:name,attr"`
+	ColFirst string   `xml:"w:colFirst,attr,omitempty"` // the first column of a bookmark in a table
+	ColLast  string   `xml:"w:colLast,attr,omitempty"`  // the last column of a bookmark in a table
+}
+
+// UnmarshalXML ...
+func (b *BookmarkStart) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	b.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	b.Name = getAtt(start.Attr, "name")
+	b.ColFirst = getAtt(start.Attr, "colFirst")
+	b.ColLast = getAtt(start.Attr, "colLast")
+	return d.Skip()
+}
+
+// BookmarkEnd <w:bookmarkEnd> ends the bookmark of ID
+type BookmarkEnd struct {
+	XMLName xml.Name `xml:"w:bookmarkEnd"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// UnmarshalXML ...
+func (b *BookmarkEnd) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	b.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	return d.Skip()
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("chapter")
+	b := p.AddBookmark("chap")
+	w.AddParagraph().AddAnchorLink("go to chapter", "chap")
+	w.AddParagraph().AddRefField("chap", "chapter")
+	if b2 := w.AddParagraph().AddBookmark("chap"); b2.Name != "chap_1" || b2.ID == b.ID {
+		t.Fatal("expected unique bookmark but has", b2.Name, b2.ID)
+	}
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := w.Bookmarks()
+	if len(bs) != 2 || bs[0].Name != "chap" {
+		t.Fatal("unexpected bookmarks", bs)
+	}
+	if _, ok := w.Document.Body.Items[0].(*Paragraph).Children[2].(*BookmarkEnd); !ok {
+		t.Fatal("expected bookmark end")
+	}
+	if s := w.Document.Body.Items[1].(*Paragraph).String(); s != "[go to chapter](#chap)" {
+		t.Fatal("unexpected anchor link", s)
+	}
+
+	nw := New().WithDefaultTheme()
+	nw.AddParagraph().AddBookmark("chap")
+	nw.AppendFile(w)
+	nw.AppendFile(w)
+	names := make(map[string]struct{}, 8)
+	ids := make(map[int]struct{}, 8)
+	for _, b := range nw.Bookmarks() {
+		names[b.Name] = struct{}{}
+		ids[b.ID] = struct{}{}
+	}
+	if len(names) != 5 || len(ids) != 5 {
+		t.Fatal("expected 5 unique bookmarks but has", names, ids)
+	}
+	links := make([]string, 0, 2)
+	for _, it := range nw.Document.Body.Items {
+		if p, ok := it.(*Paragraph); ok && len(p.Children) == 1 {
+			if h, ok := p.Children[0].(*Hyperlink); ok {
+				links = append(links, h.Anchor)
+			}
+		}
+	}
+	if len(links) != 2 || links[0] != "chap_1" || links[1] != "chap_2" {
+		t.Fatal("unexpected copied anchor links", links)
+	}
+	if fields := nw.Fields(); len(fields) != 2 || fields[1].Instruction() != `REF chap_2 \h` {
+		t.Fatal("unexpected copied ref field")
+	}
+	nw.AddParagraph().AddBookmark("R")
+	if s := nw.copiedFieldInstr(w, `REF R \h`); s != `REF R_1 \h` {
+		t.Fatal("unexpected copied field instruction", s)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Comments <w:comments> is word/comments.xml
+type Comments struct {
+	XMLName xml.Name `xml:"w:comments"`
+	XMLW    string   `xml:"xmlns:w,attr"`             // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`   // cannot be unmarshalled in
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`  // cannot be unmarshalled in
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
+
+	Comments []*Comment
+
+	name   string // name is the file name under word/
+	exname string // exname is the file name of commentsExtended under word/
+	file   *Docx
+}
+
+// UnmarshalXML ...
+func (cs *Comments) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "comment" {
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			value := Comment{file: cs.file}
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			cs.Comments = append(cs.Comments, &value)
+		}
+	}
+	return nil
+}
+
+// Comment <w:comment> is the body of a comment
+type Comment struct {
+	XMLName  xml.Name `xml:"w:comment"`
+	ID       int      `xml:"w:id,attr"`
+	Author   string   `xml:"w:author,attr,omitempty"`
+	Date     string   `xml:"w:date,attr,omitempty"`
+	Initials string   `xml:"w:initials,attr,omitempty"`
+
+	// Items are *Paragraph, *Table and *RawXML like Body.Items
+	Items []interface{}
+
+	// Parent is the comment replied by this one, stored in commentsExtended.xml
+	Parent *Comment `xml:"-"`
+	// Done is the resolved state, stored in commentsExtended.xml
+	Done bool `xml:"-"`
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (c *Comment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			c.ID, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+		case "author":
+			c.Author = attr.Value
+		case "date":
+			c.Date = attr.Value
+		case "initials":
+			c.Initials = attr.Value
+		default:
+			// ignore other attributes
+		}
+	}
+	b := Body{file: c.file}
+	err = b.UnmarshalXML(d, start)
+	c.Items = b.Items
+	return
+}
+
+// CommentsEx <w15:commentsEx> is word/commentsExtended.xml
+type CommentsEx struct {
+	XMLName    xml.Name     `xml:"w15:commentsEx"`
+	XMLW15     string       `xml:"xmlns:w15,attr"` // cannot be unmarshalled in
+	CommentExs []*CommentEx `xml:"w15:commentEx"`
+}
+
+// UnmarshalXML ...
+func (cx *CommentsEx) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok && tt.Name.Local == "commentEx" {
+			cx.CommentExs = append(cx.CommentExs, &CommentEx{
+				ParaID:       getAtt(tt.Attr, "paraId"),
+				ParaIDParent: getAtt(tt.Attr, "paraIdParent"),
+				Done:         getAtt(tt.Attr, "done"),
+			})
+		}
+	}
+	return nil
+}
+
+// CommentEx <w15:commentEx> is the extended state of the comment
+// whose last paragraph has w14:paraId ParaID
+type CommentEx struct {
+	ParaID       string `xml:"w15:paraId,attr"`
+	ParaIDParent string `xml:"w15:paraIdParent,attr,omitempty"`
+	Done         string `xml:"w15:done,attr"`
+}
+
+// CommentRangeStart <w:commentRangeStart> marks the start of the commented text
+type CommentRangeStart struct {
+	XMLName xml.Name `xml:"w:commentRangeStart"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// CommentRangeEnd <w:commentRangeEnd> marks the end of the commented text
+type CommentRangeEnd struct {
+	XMLName xml.Name `xml:"w:commentRangeEnd"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// CommentReference <w:commentReference> shows the comment mark in a run
+type CommentReference struct {
+	XMLName xml.Name `xml:"w:commentReference"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// AnnotationRef <w:annotationRef> shows the comment mark in the comment itself
+type AnnotationRef struct {
+	XMLName xml.Name `xml:"w:annotationRef"`
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("before ")
+	from := p.AddText("commented ")
+	p = w.AddParagraph()
+	to := p.AddText("text")
+	p.AddText(" after")
+	c, err := w.AddComment(from, to, "alice", "check this")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Reply("bob", "done")
+	r.Done = true
+	_, err = w.AddComment(to, from, "alice", "wrong")
+	if err != ErrInvalidRunRange {
+		t.Fatal("expected ErrInvalidRunRange but has", err)
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := w.Comments()
+	if cs == nil || len(cs.Comments) != 2 {
+		t.Fatal("expected 2 comments")
+	}
+	c, r = cs.Comment(0), cs.Comment(1)
+	if c.Author != "alice" || c.String() != "check this" || c.Done {
+		t.Fatal("unexpected comment", c.Author, c.String(), c.Done)
+	}
+	if r.Parent != c || !r.Done || r.String() != "done" {
+		t.Fatal("unexpected reply", r.Parent, r.Done, r.String())
+	}
+	if len(cs.Replies(c)) != 1 {
+		t.Fatal("expected 1 reply")
+	}
+	if s := c.AnchoredText(); s != "commented \ntext" {
+		t.Fatalf("unexpected anchored text %q", s)
+	}
+	if s := r.AnchoredText(); s != "commented \ntext" {
+		t.Fatalf("unexpected anchored text of reply %q", s)
+	}
+
+	nw := New().WithDefaultTheme()
+	nw.AppendFile(w)
+	if len(nw.Comments().Comments) != 2 {
+		t.Fatal("expected 2 copied comments")
+	}
+	if s := nw.Comments().Comment(0).AnchoredText(); s != "commented \ntext" {
+		t.Fatalf("unexpected copied anchored text %q", s)
+	}
+	if nw.Comments().Comment(1).Parent != nw.Comments().Comment(0) {
+		t.Fatal("expected copied reply")
+	}
+}
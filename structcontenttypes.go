@@ -0,0 +1,225 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	CONTENT_TYPES_NAME = `[Content_Types].xml`
+
+	XMLNS_CONTENT_TYPES = `http://schemas.openxmlformats.org/package/2006/content-types`
+
+	CONTENT_TYPE_RELS      = `application/vnd.openxmlformats-package.relationships+xml`
+	CONTENT_TYPE_XML       = `application/xml`
+	CONTENT_TYPE_DOCUMENT  = `application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml`
+	CONTENT_TYPE_STYLES    = `application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml`
+	CONTENT_TYPE_NUMBERING = `application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml`
+	CONTENT_TYPE_FOOTNOTES = `application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml`
+	CONTENT_TYPE_ENDNOTES  = `application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml`
+	CONTENT_TYPE_HEADER    = `application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml`
+	CONTENT_TYPE_FOOTER    = `application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml`
+	CONTENT_TYPE_COMMENTS  = `application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml`
+
+	CONTENT_TYPE_COMMENTS_EXTENDED = `application/vnd.openxmlformats-officedocument.wordprocessingml.commentsExtended+xml`
+
+	CONTENT_TYPE_CORE_PROPERTIES     = `application/vnd.openxmlformats-package.core-properties+xml`
+	CONTENT_TYPE_EXTENDED_PROPERTIES = `application/vnd.openxmlformats-officedocument.extended-properties+xml`
+	CONTENT_TYPE_CUSTOM_PROPERTIES   = `application/vnd.openxmlformats-officedocument.custom-properties+xml`
+
+	CONTENT_TYPE_OCTET_STREAM = `application/octet-stream`
+)
+
+// extContentTypes are the content types of the files by extension
+// that may be found in a package
+var extContentTypes = map[string]string{
+	"rels":  CONTENT_TYPE_RELS,
+	"xml":   CONTENT_TYPE_XML,
+	"png":   "image/png",
+	"jpg":   "image/jpeg",
+	"jpeg":  "image/jpeg",
+	"jpe":   "image/jpeg",
+	"jfif":  "image/jpeg",
+	"gif":   "image/gif",
+	"bmp":   "image/bmp",
+	"tif":   "image/tiff",
+	"tiff":  "image/tiff",
+	"svg":   "image/svg+xml",
+	"emf":   "image/x-emf",
+	"wmf":   "image/x-wmf",
+	"webp":  "image/webp",
+	"ico":   "image/x-icon",
+	"wdp":   "image/vnd.ms-photo",
+	"bin":   "application/vnd.openxmlformats-officedocument.oleObject",
+	"odttf": "application/vnd.openxmlformats-officedocument.obfuscatedFont",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ContentTypes is [Content_Types].xml
+type ContentTypes struct {
+	XMLName   xml.Name              `xml:"Types"`
+	Xmlns     string                `xml:"xmlns,attr"`
+	Defaults  []ContentTypeDefault  `xml:"Default"`
+	Overrides []ContentTypeOverride `xml:"Override"`
+}
+
+// ContentTypeDefault gives the content type of files by extension
+type ContentTypeDefault struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// ContentTypeOverride gives the content type of a file by its full path
+type ContentTypeOverride struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// newContentTypes makes the minimum content types of a document
+func newContentTypes() *ContentTypes {
+	return &ContentTypes{
+		Xmlns: XMLNS_CONTENT_TYPES,
+		Defaults: []ContentTypeDefault{
+			{Extension: "rels", ContentType: CONTENT_TYPE_RELS},
+			{Extension: "xml", ContentType: CONTENT_TYPE_XML},
+		},
+		Overrides: []ContentTypeOverride{
+			{PartName: "/word/document.xml", ContentType: CONTENT_TYPE_DOCUMENT},
+		},
+	}
+}
+
+// ContentType finds the content type of the part whose path is name
+// (without the leading /), or returns "" if not declared
+func (ct *ContentTypes) ContentType(name string) string {
+	name = "/" + name
+	for _, o := range ct.Overrides {
+		if strings.EqualFold(o.PartName, name) {
+			return o.ContentType
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, d := range ct.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return d.ContentType
+		}
+	}
+	return ""
+}
+
+// setDefault sets the content type of the files of extension ext
+func (ct *ContentTypes) setDefault(ext, contenttype string) {
+	for i, d := range ct.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			ct.Defaults[i].ContentType = contenttype
+			return
+		}
+	}
+	ct.Defaults = append(ct.Defaults, ContentTypeDefault{Extension: ext, ContentType: contenttype})
+}
+
+// setOverride sets the content type of the part whose path is name
+// (without the leading /)
+func (ct *ContentTypes) setOverride(name, contenttype string) {
+	name = "/" + name
+	for i, o := range ct.Overrides {
+		if o.PartName == name {
+			ct.Overrides[i].ContentType = contenttype
+			return
+		}
+	}
+	ct.Overrides = append(ct.Overrides, ContentTypeOverride{PartName: name, ContentType: contenttype})
+}
+
+// clone makes a copy of ct that can be changed on packing
+func (ct *ContentTypes) clone() *ContentTypes {
+	nct := *ct
+	nct.Defaults = append(make([]ContentTypeDefault, 0, len(ct.Defaults)+4), ct.Defaults...)
+	nct.Overrides = append(make([]ContentTypeOverride, 0, len(ct.Overrides)+8), ct.Overrides...)
+	return &nct
+}
+
+// regenerate makes ct match the files in the package: the overrides of
+// the missing parts are dropped and the defaults are exactly the extensions
+// of the files without overrides.
+func (ct *ContentTypes) regenerate(files map[string]io.Reader) {
+	names := make([]string, 0, len(files))
+	present := make(map[string]struct{}, len(files))
+	for name := range files {
+		names = append(names, name)
+		present["/"+strings.ToLower(name)] = struct{}{}
+	}
+	sort.Strings(names)
+
+	overridden := make(map[string]struct{}, len(ct.Overrides))
+	overrides := ct.Overrides[:0]
+	for _, o := range ct.Overrides {
+		n := strings.ToLower(o.PartName)
+		if _, ok := present[n]; !ok {
+			continue
+		}
+		overridden[n] = struct{}{}
+		overrides = append(overrides, o)
+	}
+	ct.Overrides = overrides
+
+	used := map[string]struct{}{"rels": {}, "xml": {}}
+	for _, name := range names {
+		if name == CONTENT_TYPES_NAME {
+			continue
+		}
+		if _, ok := overridden["/"+strings.ToLower(name)]; ok {
+			continue
+		}
+		ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+		if ext == "" {
+			continue
+		}
+		used[ext] = struct{}{}
+		if ct.ContentType(name) != "" {
+			continue
+		}
+		contenttype, ok := extContentTypes[ext]
+		if !ok {
+			contenttype = CONTENT_TYPE_OCTET_STREAM
+		}
+		ct.setDefault(ext, contenttype)
+	}
+	for _, ext := range []string{"rels", "xml"} {
+		if ct.ContentType("a."+ext) == "" {
+			ct.setDefault(ext, extContentTypes[ext])
+		}
+	}
+
+	defaults := ct.Defaults[:0]
+	for _, d := range ct.Defaults {
+		if _, ok := used[strings.ToLower(d.Extension)]; ok {
+			defaults = append(defaults, d)
+		}
+	}
+	ct.Defaults = defaults
+}
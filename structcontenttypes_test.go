@@ -0,0 +1,91 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestContentTypes(t *testing.T) {
+	w := New().WithDefaultTheme()
+	_, err := w.AddParagraph().AddInlineDrawingFrom("testdata/fumiamayoko.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.addImage("gif", []byte("GIF89a"))
+	w.addImage("svg", []byte("<svg/>"))
+	w.addImage("EMF", []byte{1})
+	w.addMedia(Media{Name: "data.unknown", Data: []byte{0}})
+	w.AddHeader().AddParagraph().AddText("header")
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.contentTypes == nil {
+		t.Fatal("expected content types to be parsed")
+	}
+	for _, tc := range [][2]string{
+		{"word/media/image100001.png", "image/png"},
+		{"word/media/image100002.gif", "image/gif"},
+		{"word/media/image100003.svg", "image/svg+xml"},
+		{"word/media/image100004.EMF", "image/x-emf"},
+		{"word/media/data.unknown", CONTENT_TYPE_OCTET_STREAM},
+		{"word/header1.xml", CONTENT_TYPE_HEADER},
+		{"word/fontTable.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml"},
+		{"docProps/core.xml", CONTENT_TYPE_CORE_PROPERTIES},
+		{"_rels/.rels", CONTENT_TYPE_RELS},
+	} {
+		ct := w.contentTypes.ContentType(tc[0])
+		if ct != tc[1] {
+			t.Fatal("expected", tc[1], "of", tc[0], "but has", ct)
+		}
+	}
+	for _, d := range w.contentTypes.Defaults {
+		if d.Extension == "jpeg" || d.Extension == "webp" {
+			t.Fatal("expected unused default", d.Extension, "to be dropped")
+		}
+	}
+
+	// the overrides of the removed parts are dropped
+	w.headers = nil
+	var buf2 bytes.Buffer
+	_, err = w.WriteTo(&buf2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf2.Bytes()), int64(buf2.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := w.contentTypes.ContentType("word/header1.xml"); ct != CONTENT_TYPE_XML {
+		t.Fatal("expected the header override to be dropped but has", ct)
+	}
+	if ct := w.contentTypes.ContentType("word/styles.xml"); ct != CONTENT_TYPE_STYLES {
+		t.Fatal("expected the styles override to be kept but has", ct)
+	}
+}
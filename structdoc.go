@@ -54,6 +54,15 @@ func getAtt(atts []xml.Attr, name string) string {
 	return ""
 }
 
+// getAttInt is getAtt with GetInt, and it returns 0 if the attribute is empty
+func getAttInt(atts []xml.Attr, name string) (int, error) {
+	v := getAtt(atts, name)
+	if v == "" {
+		return 0, nil
+	}
+	return GetInt(v)
+}
+
 // Body <w:body>
 type Body struct {
 	Items []interface{}
@@ -73,41 +82,72 @@ func (b *Body) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		}
 
 		if tt, ok := t.(xml.StartElement); ok {
-			switch tt.Name.Local {
-			case "p":
-				var value Paragraph
-				value.file = b.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			case "tbl":
-				var value Table
-				value.file = b.file
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			case "sectPr":
-				var value SectPr
-				err = d.DecodeElement(&value, &tt)
-				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
-					return err
-				}
-				b.Items = append(b.Items, &value)
-			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
-					return err
-				}
+			item, err := b.decodeItem(d, &tt)
+			if err != nil {
+				return err
 			}
+			b.Items = append(b.Items, item)
 		}
 	}
 	return nil
 }
 
+// decodeItem decodes the body item started by tt
+func (b *Body) decodeItem(d *xml.Decoder, tt *xml.StartElement) (interface{}, error) {
+	var item interface{}
+	switch tt.Name.Local {
+	case "p":
+		item = &Paragraph{file: b.file}
+	case "tbl":
+		item = &Table{file: b.file}
+	case "bookmarkStart":
+		item = &BookmarkStart{}
+	case "bookmarkEnd":
+		item = &BookmarkEnd{}
+	case "sectPr":
+		item = &SectPr{}
+	default:
+		item = &RawXML{} // keep unsupported tags
+	}
+	err := d.DecodeElement(item, tt)
+	if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+		return nil, err
+	}
+	return item, nil
+}
+
+// MarshalXML writes the last *SectPr after all other items
+// as it describes the last section of the document
+func (b *Body) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	last := -1
+	for i := len(b.Items) - 1; i >= 0; i-- {
+		if _, ok := b.Items[i].(*SectPr); ok {
+			last = i
+			break
+		}
+	}
+	for i, item := range b.Items {
+		if i == last {
+			continue
+		}
+		err = e.Encode(item)
+		if err != nil {
+			return err
+		}
+	}
+	if last >= 0 {
+		err = e.Encode(b.Items[last])
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
 // KeepElements keep named elems amd removes others
 //
 // names: *docx.Paragraph *docx.Table
@@ -129,28 +169,13 @@ func (b *Body) KeepElements(name ...string) {
 // DropDrawingOf drops all matched drawing in body
 // name: Canvas, Shape, Group, ShapeAndCanvas, ShapeAndCanvasAndGroup, NilPicture
 func (b *Body) DropDrawingOf(name string) {
-	for _, item := range b.Items {
-		switch o := item.(type) {
-		case *Paragraph:
-			f := reflect.ValueOf(o).MethodByName("Drop" + name)
-			if !f.IsValid() {
-				continue
-			}
+	walkParagraphs(b.Items, func(p *Paragraph) bool {
+		f := reflect.ValueOf(p).MethodByName("Drop" + name)
+		if f.IsValid() {
 			_ = f.Call(nil)
-		case *Table:
-			for _, tr := range o.TableRows {
-				for _, tc := range tr.TableCells {
-					for _, p := range tc.Paragraphs {
-						f := reflect.ValueOf(p).MethodByName("Drop" + name)
-						if !f.IsValid() {
-							continue
-						}
-						_ = f.Call(nil)
-					}
-				}
-			}
 		}
-	}
+		return true
+	})
 }
 
 // Document <w:document>
@@ -162,6 +187,7 @@ type Document struct {
 	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
 	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
 	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
 	// XMLMC   string   `xml:"xmlns:mc,attr,omitempty"`  // cannot be unmarshalled in
 	// XMLWP14 string   `xml:"xmlns:wp14,attr,omitempty"` // cannot be unmarshalled in
 
@@ -237,6 +263,7 @@ newdoclop:
 		ndoc.Document.XMLWPS = XMLNS_WPS
 		ndoc.Document.XMLWPC = XMLNS_WPC
 		ndoc.Document.XMLWPG = XMLNS_WPG
+		ndoc.Document.XMLW14 = XMLNS_W14
 		// ndoc.Document.XMLWP14 = XMLNS_WP14
 		ndoc.Document.XMLName.Space = XMLNS_W
 		ndoc.Document.XMLName.Local = "document"
@@ -278,8 +305,10 @@ newdoclop:
 			case *Table:
 				nt := o.copymedia(ndoc)
 				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, &nt)
+			case *SectPr:
+				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, f.copysect(o, ndoc))
 			default:
-				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, o)
+				ndoc.Document.Body.Items = append(ndoc.Document.Body.Items, ndoc.copybookmark(f, o))
 			}
 		}
 
@@ -307,28 +336,93 @@ func (r *Run) copymedia(to *Docx) *Run {
 
 func (p *Paragraph) copymedia(to *Docx) (np Paragraph) {
 	np = *p
-	np.Children = make([]interface{}, 0, len(p.Children))
 	np.file = to
-	for _, pc := range p.Children {
-		if r, ok := pc.(*Run); ok {
-			np.Children = append(np.Children, r.copymedia(to))
+	if p.file != to && p.Properties != nil && p.Properties.SectPr != nil {
+		pp := *p.Properties
+		pp.SectPr = p.file.copysect(pp.SectPr, to)
+		np.Properties = &pp
+	}
+	np.Children = p.file.copychildren(p.Children, to)
+	return
+}
+
+// copychildren copies the children of a paragraph, or of the revisions
+// and simple fields in it, from f to the file to
+func (f *Docx) copychildren(children []interface{}, to *Docx) []interface{} {
+	copyrun := func(r *Run) *Run {
+		nr := r.copymedia(to)
+		nr.InstrText = to.copiedFieldInstr(f, nr.InstrText)
+		nr.DelInstrText = to.copiedFieldInstr(f, nr.DelInstrText)
+		f.copynotes(nr, to)
+		f.copycomments(nr, to)
+		return nr
+	}
+	nc := make([]interface{}, 0, len(children))
+	for _, pc := range children {
+		switch o := pc.(type) {
+		case *Run:
+			nc = append(nc, copyrun(o))
+			continue
+		case *Revision:
+			nrv := *o
+			nrv.file = to
+			if f != nil && f != to {
+				nrv.ID = to.nextRevisionID()
+			}
+			nrv.Children = f.copychildren(o.Children, to)
+			nc = append(nc, &nrv)
+			continue
+		}
+		if f != nil && f != to {
+			switch o := pc.(type) {
+			case *CommentRangeStart:
+				if id, ok := f.copycomment(o.ID, to); ok {
+					nc = append(nc, &CommentRangeStart{ID: id})
+				}
+				continue
+			case *CommentRangeEnd:
+				if id, ok := f.copycomment(o.ID, to); ok {
+					nc = append(nc, &CommentRangeEnd{ID: id})
+				}
+				continue
+			case *BookmarkStart, *BookmarkEnd:
+				nc = append(nc, to.copybookmark(f, o))
+				continue
+			case *SimpleField:
+				nf := *o
+				nf.Instr = to.copiedFieldInstr(f, o.Instr)
+				nf.Children = f.copychildren(o.Children, to)
+				nf.file = to
+				nc = append(nc, &nf)
+				continue
+			}
+		}
+		if h, ok := pc.(*Hyperlink); ok && h.Anchor != "" {
+			anchor := h.Anchor
+			if f != nil && f != to {
+				anchor = to.copiedBookmarkName(f, anchor)
+			}
+			nc = append(nc, &Hyperlink{
+				Anchor: anchor,
+				Run:    *copyrun(&h.Run),
+			})
 			continue
 		}
 		if h, ok := pc.(*Hyperlink); ok {
-			tgt, err := p.file.ReferTarget(h.ID)
+			tgt, err := f.ReferTarget(h.ID)
 			if err != nil {
 				continue
 			}
 			rid := to.addLinkRelation(tgt)
-			np.Children = append(np.Children, &Hyperlink{
+			nc = append(nc, &Hyperlink{
 				ID:  rid,
-				Run: *h.Run.copymedia(to),
+				Run: *copyrun(&h.Run),
 			})
 			continue
 		}
-		np.Children = append(np.Children, pc)
+		nc = append(nc, pc)
 	}
-	return
+	return nc
 }
 
 func (t *Table) copymedia(to *Docx) (nt Table) {
@@ -341,11 +435,19 @@ func (t *Table) copymedia(to *Docx) (nt Table) {
 		ntr.file = to
 		for _, tc := range tr.TableCells {
 			ntc := *tc
-			ntc.Paragraphs = make([]*Paragraph, 0, len(tc.Paragraphs))
+			ntc.Items = make([]interface{}, 0, len(tc.Items))
 			ntc.file = to
-			for _, p := range tc.Paragraphs {
-				np := p.copymedia(to)
-				ntc.Paragraphs = append(ntc.Paragraphs, &np)
+			for _, it := range tc.Items {
+				switch o := it.(type) {
+				case *Paragraph:
+					np := o.copymedia(to)
+					ntc.Items = append(ntc.Items, &np)
+				case *Table:
+					nt := o.copymedia(to)
+					ntc.Items = append(ntc.Items, &nt)
+				default:
+					ntc.Items = append(ntc.Items, o)
+				}
 			}
 			ntr.TableCells = append(ntr.TableCells, &ntc)
 		}
@@ -356,6 +458,13 @@ func (t *Table) copymedia(to *Docx) (nt Table) {
 
 // AppendFile appends all contents in af to f
 func (f *Docx) AppendFile(af *Docx) {
+	// the bookmarks and comments of each appending are new ones
+	if f.bookmarks != nil {
+		delete(f.bookmarks.copiednames, af)
+		delete(f.bookmarks.copiedids, af)
+	}
+	f.copiedComments = nil
+	f.copiedParts = nil
 	for _, item := range af.Document.Body.Items {
 		switch o := item.(type) {
 		case *Paragraph:
@@ -364,8 +473,10 @@ func (f *Docx) AppendFile(af *Docx) {
 		case *Table:
 			nt := o.copymedia(f)
 			f.Document.Body.Items = append(f.Document.Body.Items, &nt)
+		case *SectPr:
+			f.Document.Body.Items = append(f.Document.Body.Items, af.copysect(o, f))
 		default:
-			f.Document.Body.Items = append(f.Document.Body.Items, o)
+			f.Document.Body.Items = append(f.Document.Body.Items, f.copybookmark(af, o))
 		}
 	}
 }
@@ -34,7 +34,7 @@ func TestUnmarshalPlainStructure(t *testing.T) {
 		numParagraphs int
 	}{
 		{decoded_doc_1, 6},
-		{decoded_doc_2, 15},
+		{decoded_doc_2, 16},
 	}
 	for _, tc := range testCases {
 		doc := Document{
@@ -59,7 +59,7 @@ func TestUnmarshalPlainStructure(t *testing.T) {
 					if child == nil {
 						t.Fatalf("There are Paragraph children with all fields nil")
 					}
-					if o, ok := child.(*Hyperlink); ok && o.ID == "" {
+					if o, ok := child.(*Hyperlink); ok && o.ID == "" && o.Anchor == "" {
 						t.Fatalf("We have a link without ID")
 					}
 				}
@@ -0,0 +1,363 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//nolint:revive,stylecheck
+const (
+	XMLNS_CP                  = `http://schemas.openxmlformats.org/package/2006/metadata/core-properties`
+	XMLNS_DC                  = `http://purl.org/dc/elements/1.1/`
+	XMLNS_DCTERMS             = `http://purl.org/dc/terms/`
+	XMLNS_DCMITYPE            = `http://purl.org/dc/dcmitype/`
+	XMLNS_XSI                 = `http://www.w3.org/2001/XMLSchema-instance`
+	XMLNS_EXTENDED_PROPERTIES = `http://schemas.openxmlformats.org/officeDocument/2006/extended-properties`
+	XMLNS_CUSTOM_PROPERTIES   = `http://schemas.openxmlformats.org/officeDocument/2006/custom-properties`
+	XMLNS_VT                  = `http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes`
+
+	// CUSTOM_PROPERTY_FMTID is the format id Word gives to all user defined properties
+	CUSTOM_PROPERTY_FMTID = `{D5CDD505-2E9C-101B-9397-08002B2CF9AE}`
+
+	VT_LPWSTR   = "lpwstr"
+	VT_I4       = "i4"
+	VT_R8       = "r8"
+	VT_BOOL     = "bool"
+	VT_FILETIME = "filetime"
+)
+
+// W3CDTF is a date of core properties like <dcterms:created xsi:type="dcterms:W3CDTF">
+type W3CDTF struct {
+	Type  string `xml:"xsi:type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// NewW3CDTF makes a W3CDTF date of t in UTC
+func NewW3CDTF(t time.Time) *W3CDTF {
+	return &W3CDTF{Type: "dcterms:W3CDTF", Value: t.UTC().Format(time.RFC3339)}
+}
+
+// Time parses the value of the date
+func (w *W3CDTF) Time() (t time.Time, err error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02", "2006-01", "2006"} {
+		t, err = time.Parse(layout, w.Value)
+		if err == nil {
+			return
+		}
+	}
+	return
+}
+
+// CoreProperties <cp:coreProperties> is docProps/core.xml
+type CoreProperties struct {
+	XMLName     xml.Name `xml:"cp:coreProperties"`
+	XMLCP       string   `xml:"xmlns:cp,attr"`       // cannot be unmarshalled in
+	XMLDC       string   `xml:"xmlns:dc,attr"`       // cannot be unmarshalled in
+	XMLDCTerms  string   `xml:"xmlns:dcterms,attr"`  // cannot be unmarshalled in
+	XMLDCMIType string   `xml:"xmlns:dcmitype,attr"` // cannot be unmarshalled in
+	XMLXSI      string   `xml:"xmlns:xsi,attr"`      // cannot be unmarshalled in
+
+	Title          string  `xml:"dc:title,omitempty"`
+	Subject        string  `xml:"dc:subject,omitempty"`
+	Creator        string  `xml:"dc:creator,omitempty"`
+	Keywords       string  `xml:"cp:keywords,omitempty"`
+	Description    string  `xml:"dc:description,omitempty"`
+	LastModifiedBy string  `xml:"cp:lastModifiedBy,omitempty"`
+	Revision       string  `xml:"cp:revision,omitempty"`
+	LastPrinted    string  `xml:"cp:lastPrinted,omitempty"`
+	Created        *W3CDTF `xml:"dcterms:created,omitempty"`
+	Modified       *W3CDTF `xml:"dcterms:modified,omitempty"`
+	Category       string  `xml:"cp:category,omitempty"`
+	ContentStatus  string  `xml:"cp:contentStatus,omitempty"`
+	Language       string  `xml:"dc:language,omitempty"`
+	Identifier     string  `xml:"dc:identifier,omitempty"`
+	Version        string  `xml:"cp:version,omitempty"`
+
+	name string // name is the path in the package like docProps/core.xml
+}
+
+// UnmarshalXML ...
+func (cp *CoreProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		tt, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var value string
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return err
+		}
+		switch tt.Name.Local {
+		case "title":
+			cp.Title = value
+		case "subject":
+			cp.Subject = value
+		case "creator":
+			cp.Creator = value
+		case "keywords":
+			cp.Keywords = value
+		case "description":
+			cp.Description = value
+		case "lastModifiedBy":
+			cp.LastModifiedBy = value
+		case "revision":
+			cp.Revision = value
+		case "lastPrinted":
+			cp.LastPrinted = value
+		case "created":
+			cp.Created = &W3CDTF{Value: value}
+			if getAtt(tt.Attr, "type") != "" {
+				cp.Created.Type = "dcterms:W3CDTF"
+			}
+		case "modified":
+			cp.Modified = &W3CDTF{Value: value}
+			if getAtt(tt.Attr, "type") != "" {
+				cp.Modified.Type = "dcterms:W3CDTF"
+			}
+		case "category":
+			cp.Category = value
+		case "contentStatus":
+			cp.ContentStatus = value
+		case "language":
+			cp.Language = value
+		case "identifier":
+			cp.Identifier = value
+		case "version":
+			cp.Version = value
+		}
+	}
+	return nil
+}
+
+// AppProperties <Properties> is docProps/app.xml, the extended properties
+type AppProperties struct {
+	XMLName xml.Name `xml:"Properties"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	XMLVT   string   `xml:"xmlns:vt,attr,omitempty"` // cannot be unmarshalled in
+
+	Template             string `xml:"Template,omitempty"`
+	Manager              string `xml:"Manager,omitempty"`
+	Company              string `xml:"Company,omitempty"`
+	Pages                int    `xml:"Pages,omitempty"`
+	Words                int    `xml:"Words,omitempty"`
+	Characters           int    `xml:"Characters,omitempty"`
+	CharactersWithSpaces int    `xml:"CharactersWithSpaces,omitempty"`
+	Lines                int    `xml:"Lines,omitempty"`
+	Paragraphs           int    `xml:"Paragraphs,omitempty"`
+	TotalTime            int    `xml:"TotalTime,omitempty"`
+	Application          string `xml:"Application,omitempty"`
+	AppVersion           string `xml:"AppVersion,omitempty"`
+
+	// Others are HeadingPairs, TitlesOfParts, DocSecurity ...
+	Others []*RawXML
+
+	name string // name is the path in the package like docProps/app.xml
+}
+
+// MarshalXML writes the unsupported elements in the default namespace of the part
+func (ap *AppProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	for _, o := range ap.Others {
+		o.space = XMLNS_EXTENDED_PROPERTIES
+	}
+	return marshalInOrder(e, start, ap, ap.Others)
+}
+
+// UnmarshalXML ...
+func (ap *AppProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		tt, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var s *string
+		var n *int
+		switch tt.Name.Local {
+		case "Template":
+			s = &ap.Template
+		case "Manager":
+			s = &ap.Manager
+		case "Company":
+			s = &ap.Company
+		case "Application":
+			s = &ap.Application
+		case "AppVersion":
+			s = &ap.AppVersion
+		case "Pages":
+			n = &ap.Pages
+		case "Words":
+			n = &ap.Words
+		case "Characters":
+			n = &ap.Characters
+		case "CharactersWithSpaces":
+			n = &ap.CharactersWithSpaces
+		case "Lines":
+			n = &ap.Lines
+		case "Paragraphs":
+			n = &ap.Paragraphs
+		case "TotalTime":
+			n = &ap.TotalTime
+		default:
+			var value RawXML
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			ap.Others = append(ap.Others, &value)
+			continue
+		}
+		var value string
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return err
+		}
+		if s != nil {
+			*s = value
+			continue
+		}
+		*n, err = GetInt(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CustomProperties <Properties> is docProps/custom.xml
+type CustomProperties struct {
+	XMLName    xml.Name `xml:"Properties"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	XMLVT      string   `xml:"xmlns:vt,attr"` // cannot be unmarshalled in
+	Properties []*CustomProperty
+
+	name string // name is the path in the package like docProps/custom.xml
+}
+
+// UnmarshalXML ...
+func (cps *CustomProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok && tt.Name.Local == "property" {
+			var value CustomProperty
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			cps.Properties = append(cps.Properties, &value)
+		}
+	}
+	return nil
+}
+
+// CustomProperty <property> is a user defined property with a typed value
+// like <vt:lpwstr>
+//
+//	Type 的常用取值：
+//		lpwstr：文本。
+//		i4：整数。
+//		r8：浮点数。
+//		bool：是或否。
+//		filetime：日期，如 2023-01-02T15:04:05Z。
+type CustomProperty struct {
+	FmtID string
+	PID   int
+	Name  string
+	Type  string // Type is the local name of the vt element
+	Value string
+}
+
+// UnmarshalXML ...
+func (p *CustomProperty) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	p.FmtID = getAtt(start.Attr, "fmtid")
+	p.Name = getAtt(start.Attr, "name")
+	p.PID, err = getAttInt(start.Attr, "pid")
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if tt, ok := t.(xml.StartElement); ok {
+			p.Type = tt.Name.Local
+			err = d.DecodeElement(&p.Value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes the value as the vt element of Type
+func (p *CustomProperty) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{
+		Name: xml.Name{Local: "property"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "fmtid"}, Value: p.FmtID},
+			{Name: xml.Name{Local: "pid"}, Value: strconv.Itoa(p.PID)},
+			{Name: xml.Name{Local: "name"}, Value: p.Name},
+		},
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	err = e.EncodeElement(p.Value, xml.StartElement{Name: xml.Name{Local: "vt:" + p.Type}})
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
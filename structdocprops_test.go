@@ -0,0 +1,171 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocProps(t *testing.T) {
+	w := New().WithDefaultTheme()
+	cp, err := w.CoreProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	cp.Title = "My Title"
+	cp.Creator = "someone"
+	cp.Keywords = "a, b"
+	cp.Revision = "3"
+	cp.Created = NewW3CDTF(created)
+	cp.Modified = NewW3CDTF(created.Add(time.Hour))
+	ap, err := w.AppProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ap.Application != "fumiama-docxlib" {
+		t.Fatal("expected app.xml of the template but has", ap.Application)
+	}
+	ap.Company = "ACME"
+	ap.Others = append(ap.Others, &RawXML{tokens: []xml.Token{
+		xml.StartElement{Name: xml.Name{Space: XMLNS_EXTENDED_PROPERTIES, Local: "DocSecurity"}},
+		xml.CharData("0"),
+		xml.EndElement{Name: xml.Name{Space: XMLNS_EXTENDED_PROPERTIES, Local: "DocSecurity"}},
+	}})
+	ap.Pages = 2
+	ap.Words = 10
+	cps, err := w.CustomProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range map[string]interface{}{
+		"str": "v", "num": 42, "big": int64(1) << 40, "pi": 3.5, "ok": true, "at": created,
+	} {
+		_, err = cps.SetProperty(name, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = cps.SetProperty("bad", []int{1}); err != ErrUnsupportedPropertyType {
+		t.Fatal("expected ErrUnsupportedPropertyType but has", err)
+	}
+	_, _ = cps.SetProperty("tmp", "x")
+	if !cps.RemoveProperty("tmp") || cps.Property("tmp") != nil {
+		t.Fatal("expected tmp to be removed")
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"_rels/.rels", "[Content_Types].xml"} {
+		f, err := w.openTemplateFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "docProps/custom.xml") {
+			t.Fatal("expected custom.xml in", name, string(data))
+		}
+	}
+
+	cp, err = w.CoreProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Title != "My Title" || cp.Creator != "someone" || cp.Keywords != "a, b" || cp.Revision != "3" {
+		t.Fatal("unexpected core properties", cp)
+	}
+	if cp.Created == nil || cp.Created.Type != "dcterms:W3CDTF" {
+		t.Fatal("expected W3CDTF created")
+	}
+	tm, err := cp.Modified.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.Equal(created.Add(time.Hour)) {
+		t.Fatal("expected modified", created.Add(time.Hour), "but has", tm)
+	}
+	ap, err = w.AppProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ap.Company != "ACME" || ap.Pages != 2 || ap.Words != 10 || ap.Template != "Normal.dotm" {
+		t.Fatal("unexpected app properties", ap)
+	}
+	if len(ap.Others) == 0 || ap.Others[len(ap.Others)-1].String() != "0" {
+		t.Fatal("expected the unsupported app properties")
+	}
+	var apbuf bytes.Buffer
+	_, err = marshaller{data: ap}.WriteTo(&apbuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := "<DocSecurity>0</DocSecurity>"; !strings.Contains(apbuf.String(), s) || strings.Contains(apbuf.String(), "xmlns:ns") {
+		t.Fatal("expected", s, "in the default namespace but has", apbuf.String())
+	}
+	cps, err = w.CustomProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cps.Properties) != 6 {
+		t.Fatal("expected 6 custom properties but has", len(cps.Properties))
+	}
+	for name, v := range map[string]interface{}{
+		"str": "v", "num": int64(42), "big": int64(1) << 40, "pi": 3.5, "ok": true,
+	} {
+		p := cps.Property(name)
+		if p == nil {
+			t.Fatal("expected custom property", name)
+		}
+		x, err := p.Val()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != v {
+			t.Fatal("expected", v, "of", name, "but has", x)
+		}
+	}
+	x, err := cps.Property("at").Val()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !x.(time.Time).Equal(created) || cps.Property("at").Type != VT_FILETIME {
+		t.Fatal("expected filetime", created, "but has", x)
+	}
+	if cps.Property("big").Type != "i8" || cps.Property("num").Type != VT_I4 {
+		t.Fatal("unexpected integer types")
+	}
+}
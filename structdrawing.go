@@ -406,6 +406,8 @@ type WPDocPr struct {
 	XMLName xml.Name `xml:"wp:docPr,omitempty"`
 	ID      int      `xml:"id,attr"`
 	Name    string   `xml:"name,attr,omitempty"`
+	Descr   string   `xml:"descr,attr,omitempty"` // the alt text
+	Title   string   `xml:"title,attr,omitempty"`
 }
 
 // UnmarshalXML ...
@@ -420,7 +422,10 @@ func (r *WPDocPr) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			r.ID = id
 		case "name":
 			r.Name = attr.Value
-
+		case "descr":
+			r.Descr = attr.Value
+		case "title":
+			r.Title = attr.Value
 		default:
 			// ignore other attributes
 		}
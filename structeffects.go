@@ -104,6 +104,13 @@ type TextAlignment struct {
 	Val     string   `xml:"w:val,attr"`
 }
 
+// OutlineLvl <w:outlineLvl> is the outline level of the paragraph,
+// 0 for level 1 and 9 for body text
+type OutlineLvl struct {
+	XMLName xml.Name `xml:"w:outlineLvl,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
 // VertAlign ...
 type VertAlign struct {
 	XMLName xml.Name `xml:"w:vertAlign,omitempty"`
@@ -116,6 +123,12 @@ type Strike struct {
 	Val     string   `xml:"w:val,attr"`
 }
 
+// Vanish hides the text of the run
+type Vanish struct {
+	XMLName xml.Name `xml:"w:vanish,omitempty"`
+	Val     string   `xml:"w:val,attr,omitempty"`
+}
+
 // Shade is an element that represents a shading pattern applied to a document element.
 type Shade struct {
 	XMLName       xml.Name `xml:"w:shd,omitempty"`
@@ -285,6 +298,8 @@ type Spacing struct {
 
 	BeforeLines int    `xml:"w:beforeLines,attr,omitempty"`
 	Before      int    `xml:"w:before,attr,omitempty"`
+	AfterLines  *int   `xml:"w:afterLines,attr,omitempty"` // keep an explicit 0 against the inherited one
+	After       *int   `xml:"w:after,attr,omitempty"`      // keep an explicit 0 against the inherited one
 	Line        int    `xml:"w:line,attr,omitempty"`
 	LineRule    string `xml:"w:lineRule,attr,omitempty"`
 }
@@ -308,6 +323,20 @@ func (s *Spacing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err erro
 			if err != nil {
 				return
 			}
+		case "afterLines":
+			var v int
+			v, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+			s.AfterLines = &v
+		case "after":
+			var v int
+			v, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+			s.After = &v
 		case "line":
 			s.Line, err = GetInt(attr.Value)
 			if err != nil {
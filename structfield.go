@@ -0,0 +1,97 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	FIELD_CHAR_BEGIN    = "begin"
+	FIELD_CHAR_SEPARATE = "separate"
+	FIELD_CHAR_END      = "end"
+)
+
+// FieldChar <w:fldChar> marks the parts of a complex field
+//
+//	w:fldCharType 属性的取值可以是以下之一：
+//		begin：域开始，其后为 w:instrText 域代码。
+//		separate：域代码结束，其后为域结果。
+//		end：域结束。
+type FieldChar struct {
+	XMLName xml.Name `xml:"w:fldChar,omitempty"`
+	Type    string   `xml:"w:fldCharType,attr"`
+	Dirty   bool     `xml:"w:dirty,attr,omitempty"` // ask the reader to update the field
+
+	Others []*RawXML // unsupported elements like w:ffData kept as is
+}
+
+// UnmarshalXML ...
+func (fc *FieldChar) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	fc.Type = getAtt(start.Attr, "fldCharType")
+	v := getAtt(start.Attr, "dirty")
+	fc.Dirty = v == "true" || v == "1" || v == "on"
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			var value RawXML
+			err = d.DecodeElement(&value, &tt) // keep unsupported tags
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			fc.Others = append(fc.Others, &value)
+		}
+	}
+	return nil
+}
+
+// SimpleField <w:fldSimple> is a field whose instruction is in w:instr
+// and whose cached result is in the runs inside it
+type SimpleField struct {
+	XMLName xml.Name `xml:"w:fldSimple,omitempty"`
+	Instr   string   `xml:"w:instr,attr"`
+	Dirty   bool     `xml:"w:dirty,attr,omitempty"` // ask the reader to update the field
+
+	// Children are *Run, *Hyperlink and *RawXML like Paragraph.Children
+	Children []interface{}
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (sf *SimpleField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	sf.Instr = getAtt(start.Attr, "instr")
+	v := getAtt(start.Attr, "dirty")
+	sf.Dirty = v == "true" || v == "1" || v == "on"
+	p := Paragraph{file: sf.file}
+	err := p.UnmarshalXML(d, xml.StartElement{Name: start.Name})
+	sf.Children = p.Children
+	return err
+}
@@ -0,0 +1,149 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+const field_doc = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	`<w:p><w:fldSimple w:instr=" NUMPAGES "><w:r><w:t>3</w:t></w:r></w:fldSimple>` +
+	`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> IF </w:instrText></w:r>` +
+	`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText>PAGE</w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+	`<w:r><w:t>2</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r>` +
+	`<w:r><w:instrText xml:space="preserve"> = 2 "two" "other"</w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+	`<w:r><w:t>tw</w:t></w:r></w:p><w:p><w:r><w:t>o</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+	`</w:body></w:document>`
+
+func TestParseFields(t *testing.T) {
+	w := New()
+	err := xml.Unmarshal(StringToBytes(field_doc), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := w.Fields()
+	if len(fields) != 3 {
+		t.Fatal("expected 3 fields but has", len(fields))
+	}
+	if fields[0].Type() != "NUMPAGES" || fields[0].ResultText() != "3" {
+		t.Fatal("unexpected simple field", fields[0].Instruction(), fields[0].ResultText())
+	}
+	outer, inner := fields[1], fields[2]
+	if outer.Instruction() != `IF  = 2 "two" "other"` || outer.ResultText() != "two" {
+		t.Fatalf("unexpected outer field %q %q", outer.Instruction(), outer.ResultText())
+	}
+	if inner.Type() != "PAGE" || inner.ResultText() != "2" {
+		t.Fatalf("unexpected inner field %q %q", inner.Instruction(), inner.ResultText())
+	}
+	inner.SetResult("5")
+	outer.SetResult("other")
+	fields[0].SetInstruction("SECTIONPAGES")
+	fields = w.Fields()
+	if len(fields) != 3 || fields[0].Type() != "SECTIONPAGES" || fields[1].ResultText() != "other" || fields[2].ResultText() != "5" {
+		t.Fatal("unexpected updated fields")
+	}
+	if s := w.Document.Body.Items[1].(*Paragraph).String(); s != "" {
+		t.Fatalf("unexpected result left %q", s)
+	}
+}
+
+func TestParseNestedFields(t *testing.T) {
+	w := New()
+	err := xml.Unmarshal(StringToBytes(decoded_doc_2), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := make([]*Field, 0, 4)
+	for _, fd := range w.Fields() {
+		if fd.Type() == "PAGEREF" {
+			refs = append(refs, fd)
+		}
+	}
+	if len(refs) != 4 || refs[0].Instruction() != `PAGEREF _Toc420414504 \h` || refs[0].ResultText() != "2" {
+		t.Fatal("expected 4 PAGEREF fields in hyperlinks but has", len(refs))
+	}
+	refs[0].SetResult("3")
+	if fd := w.Fields()[1]; fd.ResultText() != "3" {
+		t.Fatal("unexpected result in hyperlink", fd.ResultText())
+	}
+
+	w = New()
+	err = xml.Unmarshal(StringToBytes(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p>`+
+		`<w:ins w:id="1" w:author="a"><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText>PAGE</w:instrText></w:r>`+
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>1</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:ins>`+
+		`</w:p></w:body></w:document>`), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := w.Fields()
+	if len(fields) != 1 || fields[0].Type() != "PAGE" {
+		t.Fatal("expected the field in revision")
+	}
+	fields[0].SetInstruction("NUMPAGES")
+	fields[0].SetResult("2")
+	rev := w.Document.Body.Items[0].(*Paragraph).Children[0].(*Revision)
+	if len(rev.Children) != 5 || w.Fields()[0].Type() != "NUMPAGES" || w.Fields()[0].ResultText() != "2" {
+		t.Fatal("unexpected updated field in revision")
+	}
+}
+
+func TestAddFields(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("Date: ")
+	p.AddDateField("yyyy-MM-dd")
+	w.AddParagraph().AddRefField("target", "see here")
+	ft := w.AddFooter()
+	fp := ft.AddParagraph()
+	fp.AddPageField()
+	fp.AddText(" / ")
+	fp.AddNumPagesField()
+	w.SectPr().WithFooter("default", ft)
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := w.Fields()
+	if len(fields) != 4 {
+		t.Fatal("expected 4 fields but has", len(fields))
+	}
+	if fields[0].Instruction() != `DATE \@ "yyyy-MM-dd"` || fields[0].ResultText() != time.Now().Format("2006-01-02") {
+		t.Fatal("unexpected date field", fields[0].Instruction(), fields[0].ResultText())
+	}
+	if fields[1].Type() != "REF" || !fields[1].Begin.Children[0].(*FieldChar).Dirty {
+		t.Fatal("unexpected ref field", fields[1].Instruction())
+	}
+	if fields[2].Type() != "PAGE" || fields[3].Type() != "NUMPAGES" {
+		t.Fatal("unexpected footer fields", fields[2].Type(), fields[3].Type())
+	}
+	if s := w.Footers()[0].Items[0].(*Paragraph).String(); s != "1 / 1" {
+		t.Fatalf("unexpected footer %q", s)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+)
+
+// Header <w:hdr> is word/headerN.xml
+type Header struct {
+	XMLName xml.Name `xml:"w:hdr"`
+	XMLW    string   `xml:"xmlns:w,attr"`             // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`   // cannot be unmarshalled in
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`  // cannot be unmarshalled in
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
+
+	// Items are *Paragraph, *Table and *RawXML like Body.Items
+	Items []interface{}
+
+	id   string // id is the rId in word/_rels/document.xml.rels
+	name string // name is the file name under word/
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (h *Header) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b := Body{file: h.file}
+	err := b.UnmarshalXML(d, start)
+	h.Items = b.Items
+	return err
+}
+
+// Footer <w:ftr> is word/footerN.xml
+type Footer struct {
+	XMLName xml.Name `xml:"w:ftr"`
+	XMLW    string   `xml:"xmlns:w,attr"`             // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`   // cannot be unmarshalled in
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`  // cannot be unmarshalled in
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
+
+	// Items are *Paragraph, *Table and *RawXML like Body.Items
+	Items []interface{}
+
+	id   string // id is the rId in word/_rels/document.xml.rels
+	name string // name is the file name under word/
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (ft *Footer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b := Body{file: ft.file}
+	err := b.UnmarshalXML(d, start)
+	ft.Items = b.Items
+	return err
+}
+
+// HeaderReference <w:headerReference> binds a header to the section
+//
+//	w:type 属性的取值可以是以下之一：
+//		default：默认页眉。
+//		first：首页页眉，需要 w:titlePg。
+//		even：偶数页页眉，需要 settings.xml 中的 w:evenAndOddHeaders。
+type HeaderReference struct {
+	XMLName xml.Name `xml:"w:headerReference,omitempty"`
+	Type    string   `xml:"w:type,attr"`
+	ID      string   `xml:"r:id,attr"`
+}
+
+// FooterReference <w:footerReference> binds a footer to the section
+//
+//	w:type 属性的取值可以是以下之一：
+//		default：默认页脚。
+//		first：首页页脚，需要 w:titlePg。
+//		even：偶数页页脚，需要 settings.xml 中的 w:evenAndOddHeaders。
+type FooterReference struct {
+	XMLName xml.Name `xml:"w:footerReference,omitempty"`
+	Type    string   `xml:"w:type,attr"`
+	ID      string   `xml:"r:id,attr"`
+}
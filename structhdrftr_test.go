@@ -0,0 +1,167 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFooterRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddParagraph().AddText("body")
+	h := w.AddHeader()
+	h.AddParagraph().AddLink("home", "https://example.com")
+	ft := w.AddFooter()
+	ft.AddParagraph().AddText("footer text")
+	w.SectPr().WithHeader("default", h).WithFooter("default", ft)
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Headers()) != 1 || len(w.Footers()) != 1 {
+		t.Fatal("expected 1 header and 1 footer but has", len(w.Headers()), len(w.Footers()))
+	}
+	sect := w.SectPr()
+	if len(sect.HeaderReferences) != 1 || len(sect.FooterReferences) != 1 {
+		t.Fatal("expected references in sectPr")
+	}
+	h = w.Header(sect.HeaderReferences[0].ID)
+	if h == nil {
+		t.Fatal("header", sect.HeaderReferences[0].ID, "not found")
+	}
+	link := h.Items[0].(*Paragraph).Children[0].(*Hyperlink)
+	target, err := w.ReferTarget(link.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != "https://example.com" {
+		t.Fatal("expected https://example.com but has", target)
+	}
+	for _, r := range w.docRelation.Relationship {
+		if r.ID == link.ID {
+			t.Fatal("expected the link related in the header only")
+		}
+	}
+	if rels := w.partRels[h.name]; rels == nil || len(rels.Relationship) != 1 || rels.Relationship[0].ID != link.ID {
+		t.Fatal("expected the relationships of the header but has", rels)
+	}
+	ft = w.Footer(sect.FooterReferences[0].ID)
+	if ft == nil {
+		t.Fatal("footer", sect.FooterReferences[0].ID, "not found")
+	}
+	if s := ft.Items[0].(*Paragraph).String(); !strings.Contains(s, "footer text") {
+		t.Fatal("expected footer text but has", s)
+	}
+
+	var buf2 bytes.Buffer // the parsed file still reads from buf
+	_, err = w.WriteTo(&buf2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf2.Bytes()), int64(buf2.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Headers()) != 1 || len(w.Footers()) != 1 {
+		t.Fatal("expected 1 header and 1 footer after repacking but has", len(w.Headers()), len(w.Footers()))
+	}
+}
+
+func TestCopyHeaderFooter(t *testing.T) {
+	w := New().WithDefaultTheme()
+	h := w.AddHeader()
+	h.AddParagraph().AddLink("home", "https://example.com")
+	ft := w.AddFooter()
+	ft.AddParagraph().AddText("footer text")
+	w.AddParagraph().AddText("first")
+	w.AddParagraph().EndSection().WithHeader("default", h).WithFooter("default", ft)
+	w.AddParagraph().AddText("SPLIT second")
+	w.SectPr().WithHeader("default", h).WithFooter("default", ft)
+
+	check := func(w *Docx, sects int) {
+		t.Helper()
+		var buf bytes.Buffer
+		_, err := w.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, it := range w.Document.Body.Items {
+			var sect *SectPr
+			switch o := it.(type) {
+			case *SectPr:
+				sect = o
+			case *Paragraph:
+				if o.Properties != nil {
+					sect = o.Properties.SectPr
+				}
+			}
+			if sect == nil {
+				continue
+			}
+			n++
+			if len(sect.HeaderReferences) != 1 || len(sect.FooterReferences) != 1 {
+				t.Fatal("expected references in sectPr")
+			}
+			h := w.Header(sect.HeaderReferences[0].ID)
+			if h == nil {
+				t.Fatal("header", sect.HeaderReferences[0].ID, "not found")
+			}
+			target, err := w.ReferTarget(h.Items[0].(*Paragraph).Children[0].(*Hyperlink).ID)
+			if err != nil || target != "https://example.com" {
+				t.Fatal("unexpected link in header", target, err)
+			}
+			ft := w.Footer(sect.FooterReferences[0].ID)
+			if ft == nil || !strings.Contains(ft.Items[0].(*Paragraph).String(), "footer text") {
+				t.Fatal("footer", sect.FooterReferences[0].ID, "not found")
+			}
+		}
+		if n != sects {
+			t.Fatal("expected", sects, "sections but has", n)
+		}
+		if len(w.Headers()) != 1 || len(w.Footers()) != 1 {
+			t.Fatal("expected the parts copied once but has", len(w.Headers()), len(w.Footers()))
+		}
+	}
+
+	nw := New().WithDefaultTheme()
+	nw.AppendFile(w)
+	check(nw, 2)
+	docs := w.SplitByParagraph(SplitDocxByPlainTextRegex(regexp.MustCompile("SPLIT")))
+	if len(docs) != 2 {
+		t.Fatal("expected 2 docs but has", len(docs))
+	}
+	check(docs[0], 1)
+	check(docs[1], 1)
+}
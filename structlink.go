@@ -29,7 +29,8 @@ import (
 // Hyperlink element contains links
 type Hyperlink struct {
 	XMLName xml.Name `xml:"w:hyperlink,omitempty"`
-	ID      string   `xml:"r:id,attr"`
+	ID      string   `xml:"r:id,attr,omitempty"`     // ID is the rId of an external target
+	Anchor  string   `xml:"w:anchor,attr,omitempty"` // Anchor is the name of a bookmark in the document
 	Run     Run
 }
 
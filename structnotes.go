@@ -0,0 +1,166 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Footnotes <w:footnotes> is word/footnotes.xml
+type Footnotes struct {
+	XMLName xml.Name `xml:"w:footnotes"`
+	XMLW    string   `xml:"xmlns:w,attr"`             // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`   // cannot be unmarshalled in
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`  // cannot be unmarshalled in
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
+
+	Notes []*Note
+
+	name string // name is the file name under word/
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (fn *Footnotes) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) (err error) {
+	fn.Notes, err = parseNotes(d, fn.file)
+	return
+}
+
+// Endnotes <w:endnotes> is word/endnotes.xml
+type Endnotes struct {
+	XMLName xml.Name `xml:"w:endnotes"`
+	XMLW    string   `xml:"xmlns:w,attr"`             // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"`   // cannot be unmarshalled in
+	XMLWP   string   `xml:"xmlns:wp,attr,omitempty"`  // cannot be unmarshalled in
+	XMLWPS  string   `xml:"xmlns:wps,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPC  string   `xml:"xmlns:wpc,attr,omitempty"` // cannot be unmarshalled in
+	XMLWPG  string   `xml:"xmlns:wpg,attr,omitempty"` // cannot be unmarshalled in
+	XMLW14  string   `xml:"xmlns:w14,attr,omitempty"` // cannot be unmarshalled in
+
+	Notes []*Note
+
+	name string // name is the file name under word/
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (en *Endnotes) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) (err error) {
+	en.Notes, err = parseNotes(d, en.file)
+	return
+}
+
+func parseNotes(d *xml.Decoder, file *Docx) (notes []*Note, err error) {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "footnote" && tt.Name.Local != "endnote" {
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			value := Note{file: file}
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return nil, err
+			}
+			notes = append(notes, &value)
+		}
+	}
+	return notes, nil
+}
+
+// Note <w:footnote> or <w:endnote> is the body of a note
+//
+//	w:type 属性的取值可以是以下之一：
+//		空：普通的脚注或尾注。
+//		separator：正文与注释之间的分隔线。
+//		continuationSeparator：注释跨页时的分隔线。
+//		continuationNotice：注释跨页时的提示。
+type Note struct {
+	XMLName xml.Name // w:footnote or w:endnote
+	Type    string   `xml:"w:type,attr,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+
+	// Items are *Paragraph, *Table and *RawXML like Body.Items
+	Items []interface{}
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (n *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	n.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	n.Type = getAtt(start.Attr, "type")
+	n.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	b := Body{file: n.file}
+	err = b.UnmarshalXML(d, start)
+	n.Items = b.Items
+	return
+}
+
+// FootnoteReference <w:footnoteReference> shows the number of footnote ID in body
+type FootnoteReference struct {
+	XMLName xml.Name `xml:"w:footnoteReference"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// EndnoteReference <w:endnoteReference> shows the number of endnote ID in body
+type EndnoteReference struct {
+	XMLName xml.Name `xml:"w:endnoteReference"`
+	ID      int      `xml:"w:id,attr"`
+}
+
+// FootnoteRef <w:footnoteRef> shows the number of the footnote in itself
+type FootnoteRef struct {
+	XMLName xml.Name `xml:"w:footnoteRef"`
+}
+
+// EndnoteRef <w:endnoteRef> shows the number of the endnote in itself
+type EndnoteRef struct {
+	XMLName xml.Name `xml:"w:endnoteRef"`
+}
+
+// Separator <w:separator> is the line between body and notes
+type Separator struct {
+	XMLName xml.Name `xml:"w:separator"`
+}
+
+// ContinuationSeparator <w:continuationSeparator> is the line
+// between body and the notes continued from the previous page
+type ContinuationSeparator struct {
+	XMLName xml.Name `xml:"w:continuationSeparator"`
+}
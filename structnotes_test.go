@@ -0,0 +1,84 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestNotes(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("see")
+	p.AddFootnote("the first footnote").AddParagraph().AddText("more")
+	w.AddParagraph().AddText("SPLIT")
+	p = w.AddParagraph()
+	p.AddText("and")
+	p.AddEndnote("an endnote")
+	p.AddFootnote("the second footnote")
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Footnotes().Notes) != 4 || len(w.Endnotes().Notes) != 3 {
+		t.Fatal("expected 4 footnotes and 3 endnotes but has", len(w.Footnotes().Notes), len(w.Endnotes().Notes))
+	}
+	if w.Footnotes().Note(-1).Type != "separator" || w.Endnotes().Note(0).Type != "continuationSeparator" {
+		t.Fatal("expected separators")
+	}
+	if s := w.Footnotes().Note(1).String(); s != " the first footnote\nmore" {
+		t.Fatal("unexpected footnote", s)
+	}
+	ref := w.Document.Body.Items[2].(*Paragraph).Children[2].(*Run).Children[0].(*FootnoteReference)
+	if ref.ID != 2 {
+		t.Fatal("expected footnote 2 but has", ref.ID)
+	}
+
+	nw := New().WithDefaultTheme()
+	nw.AddParagraph().AddFootnote("existing")
+	nw.AppendFile(w)
+	if len(nw.Footnotes().Notes) != 5 || len(nw.Endnotes().Notes) != 3 {
+		t.Fatal("expected 5 footnotes and 3 endnotes but has", len(nw.Footnotes().Notes), len(nw.Endnotes().Notes))
+	}
+	ref = nw.Document.Body.Items[3].(*Paragraph).Children[2].(*Run).Children[0].(*FootnoteReference)
+	if s := nw.Footnotes().Note(ref.ID).String(); s != " the second footnote" {
+		t.Fatal("unexpected copied footnote", ref.ID, s)
+	}
+
+	docs := w.SplitByParagraph(SplitDocxByPlainTextRegex(regexp.MustCompile("SPLIT")))
+	if len(docs) != 2 {
+		t.Fatal("expected 2 docs but has", len(docs))
+	}
+	if len(docs[0].Footnotes().Notes) != 3 || docs[0].Endnotes() != nil {
+		t.Fatal("unexpected notes in doc 0")
+	}
+	if docs[1].Endnotes().Note(1).String() != " an endnote" {
+		t.Fatal("unexpected endnote in doc 1")
+	}
+}
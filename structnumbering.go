@@ -0,0 +1,473 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Numbering <w:numbering> is word/numbering.xml
+type Numbering struct {
+	XMLName xml.Name `xml:"w:numbering"`
+	XMLW    string   `xml:"xmlns:w,attr"`           // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"` // cannot be unmarshalled in
+
+	Others []*RawXML // unsupported elements kept as is, like numPicBullet
+
+	AbstractNums []*AbstractNum
+	Nums         []*Num
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (n *Numbering) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, n, n.Others)
+}
+
+// UnmarshalXML ...
+func (n *Numbering) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
+			switch tt.Name.Local {
+			case "abstractNum":
+				var value AbstractNum
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				n.AbstractNums = append(n.AbstractNums, &value)
+			case "num":
+				value := Num{numbering: n}
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				n.Nums = append(n.Nums, &value)
+			default:
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				n.Others = append(n.Others, value.placeAfter(after))
+			}
+		}
+	}
+	return nil
+}
+
+// AbstractNum <w:abstractNum> defines the levels of a list
+type AbstractNum struct {
+	XMLName        xml.Name `xml:"w:abstractNum"`
+	AbstractNumID  int      `xml:"w:abstractNumId,attr"`
+	MultiLevelType *MultiLevelType
+
+	Others []*RawXML // unsupported elements kept as is, like nsid and tmpl
+
+	Levels []*NumLevel
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (a *AbstractNum) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, a, a.Others)
+}
+
+// UnmarshalXML ...
+func (a *AbstractNum) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	a.AbstractNumID, err = getAttInt(start.Attr, "abstractNumId")
+	if err != nil {
+		return
+	}
+	prev := "" // the previous sibling of the unsupported tags
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
+			switch tt.Name.Local {
+			case "multiLevelType":
+				a.MultiLevelType = &MultiLevelType{Val: getAtt(tt.Attr, "val")}
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+			case "lvl":
+				var value NumLevel
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				a.Levels = append(a.Levels, &value)
+			default:
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				a.Others = append(a.Others, value.placeAfter(after))
+			}
+		}
+	}
+	return nil
+}
+
+// MultiLevelType <w:multiLevelType>
+//
+//	w:val 属性的取值可以是以下之一：
+//		singleLevel：单级列表。
+//		multilevel：多级列表。
+//		hybridMultilevel：每级可单独使用的多级列表。
+type MultiLevelType struct {
+	XMLName xml.Name `xml:"w:multiLevelType,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// NumLevel <w:lvl> is the appearance of a level in list
+type NumLevel struct {
+	XMLName    xml.Name `xml:"w:lvl"`
+	Ilvl       int      `xml:"w:ilvl,attr"`
+	Start      *NumStart
+	NumFmt     *NumFmt
+	LvlRestart *LvlRestart
+	PStyle     *Style
+	IsLgl      *IsLgl
+	Suff       *Suff
+	LvlText    *LvlText
+	LvlJc      *LvlJc
+
+	Others []*RawXML // unsupported elements kept as is
+
+	ParagraphProperties *ParagraphProperties
+	RunProperties       *RunProperties
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (l *NumLevel) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, l, l.Others)
+}
+
+// UnmarshalXML ...
+func (l *NumLevel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	l.Ilvl, err = getAttInt(start.Attr, "ilvl")
+	if err != nil {
+		return
+	}
+	prev := "" // the previous sibling of the unsupported tags
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
+			var v int
+			switch tt.Name.Local {
+			case "start":
+				v, err = getAttInt(tt.Attr, "val")
+				l.Start = &NumStart{Val: v}
+			case "numFmt":
+				l.NumFmt = &NumFmt{Val: getAtt(tt.Attr, "val")}
+			case "lvlRestart":
+				v, err = getAttInt(tt.Attr, "val")
+				l.LvlRestart = &LvlRestart{Val: v}
+			case "pStyle":
+				l.PStyle = &Style{Val: getAtt(tt.Attr, "val")}
+			case "isLgl":
+				l.IsLgl = &IsLgl{}
+			case "suff":
+				l.Suff = &Suff{Val: getAtt(tt.Attr, "val")}
+			case "lvlText":
+				l.LvlText = &LvlText{Val: getAtt(tt.Attr, "val")}
+			case "lvlJc":
+				l.LvlJc = &LvlJc{Val: getAtt(tt.Attr, "val")}
+			case "pPr":
+				var value ParagraphProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				l.ParagraphProperties = &value
+				continue
+			case "rPr":
+				var value RunProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				l.RunProperties = &value
+				continue
+			default:
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				l.Others = append(l.Others, value.placeAfter(after))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NumStart <w:start> is the starting value of a level
+type NumStart struct {
+	XMLName xml.Name `xml:"w:start,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// NumFmt <w:numFmt> is the format of the number
+//
+//	w:val 属性常见的取值有：
+//		bullet：项目符号。
+//		decimal：阿拉伯数字。
+//		upperRoman / lowerRoman：大写 / 小写罗马数字。
+//		upperLetter / lowerLetter：大写 / 小写字母。
+//		chineseCounting：中文数字。
+//		none：无编号。
+type NumFmt struct {
+	XMLName xml.Name `xml:"w:numFmt,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LvlRestart <w:lvlRestart> restarts the level after the level Val-1 appears
+type LvlRestart struct {
+	XMLName xml.Name `xml:"w:lvlRestart,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// IsLgl <w:isLgl> shows all numbers of the upper levels in decimal
+type IsLgl struct {
+	XMLName xml.Name `xml:"w:isLgl,omitempty"`
+}
+
+// Suff <w:suff> is the character after the number: tab, space or nothing
+type Suff struct {
+	XMLName xml.Name `xml:"w:suff,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LvlText <w:lvlText> is the text of the number, in which %1 to %9
+// will be replaced by the numbers of level 0 to 8, like %1.%2.
+type LvlText struct {
+	XMLName xml.Name `xml:"w:lvlText,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LvlJc <w:lvlJc> is the alignment of the number: left, center or right
+type LvlJc struct {
+	XMLName xml.Name `xml:"w:lvlJc,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Num <w:num> is a list instance referred by paragraphs by numId
+type Num struct {
+	XMLName       xml.Name `xml:"w:num"`
+	NumID         int      `xml:"w:numId,attr"`
+	AbstractNumID *AbstractNumID
+	LvlOverrides  []*LvlOverride
+
+	numbering *Numbering
+}
+
+// UnmarshalXML ...
+func (n *Num) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	n.NumID, err = getAttInt(start.Attr, "numId")
+	if err != nil {
+		return
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "abstractNumId":
+				var v int
+				v, err = getAttInt(tt.Attr, "val")
+				if err != nil {
+					return err
+				}
+				n.AbstractNumID = &AbstractNumID{Val: v}
+				err = d.Skip()
+			case "lvlOverride":
+				var value LvlOverride
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				n.LvlOverrides = append(n.LvlOverrides, &value)
+				err = nil
+			default:
+				err = d.Skip() // skip unsupported tags
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AbstractNumID <w:abstractNumId> refers to the definition of the list
+type AbstractNumID struct {
+	XMLName xml.Name `xml:"w:abstractNumId,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// LvlOverride <w:lvlOverride> overrides a level of the definition in a list
+type LvlOverride struct {
+	XMLName       xml.Name `xml:"w:lvlOverride"`
+	Ilvl          int      `xml:"w:ilvl,attr"`
+	StartOverride *StartOverride
+	Level         *NumLevel
+}
+
+// UnmarshalXML ...
+func (o *LvlOverride) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	o.Ilvl, err = getAttInt(start.Attr, "ilvl")
+	if err != nil {
+		return
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "startOverride":
+				var v int
+				v, err = getAttInt(tt.Attr, "val")
+				if err != nil {
+					return err
+				}
+				o.StartOverride = &StartOverride{Val: v}
+				err = d.Skip()
+			case "lvl":
+				var value NumLevel
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				o.Level = &value
+				err = nil
+			default:
+				err = d.Skip() // skip unsupported tags
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StartOverride <w:startOverride> restarts the level from Val
+type StartOverride struct {
+	XMLName xml.Name `xml:"w:startOverride,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// NumPr <w:numPr> makes the paragraph an item of list NumID at level Ilvl
+type NumPr struct {
+	XMLName xml.Name `xml:"w:numPr,omitempty"`
+	Ilvl    *Ilvl
+	NumID   *NumID
+}
+
+// UnmarshalXML ...
+func (n *NumPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			var v int
+			v, err = getAttInt(tt.Attr, "val")
+			if err != nil {
+				return err
+			}
+			switch tt.Name.Local {
+			case "ilvl":
+				n.Ilvl = &Ilvl{Val: v}
+			case "numId":
+				n.NumID = &NumID{Val: v}
+			}
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Ilvl <w:ilvl> is the level of the list item, from 0 to 8
+type Ilvl struct {
+	XMLName xml.Name `xml:"w:ilvl,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// NumID <w:numId> refers to the list, and 0 removes numbering from the paragraph
+type NumID struct {
+	XMLName xml.Name `xml:"w:numId,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestNumberingRoundTrip(t *testing.T) {
+	w := New().WithDefaultTheme()
+	bullets, err := w.AddList(LIST_BULLET)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers, err := w.AddList(LIST_DECIMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers.Level(1).WithFormat(LIST_UPPER_ROMAN, "(%2)")
+	w.AddParagraph().ListItem(bullets, 0).AddText("apple")
+	w.AddParagraph().ListItem(bullets, 1).AddText("banana")
+	w.AddParagraph().ListItem(numbers, 0).AddText("one")
+	w.AddParagraph().ListItem(numbers, 1).AddText("one.I")
+	w.AddParagraph().ListItem(numbers.Restart(1), 0).AddText("one again")
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := w.Numbering()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.AbstractNums) != 2 || len(n.Nums) != 3 {
+		t.Fatal("expected 2 abstractNums and 3 nums but has", len(n.AbstractNums), len(n.Nums))
+	}
+	expected := []struct{ numID, ilvl int }{{1, 0}, {1, 1}, {2, 0}, {2, 1}, {3, 0}}
+	for i, e := range expected {
+		numPr := w.Document.Body.Items[i].(*Paragraph).Properties.NumPr
+		if numPr == nil || numPr.NumID.Val != e.numID || numPr.Ilvl.Val != e.ilvl {
+			t.Fatal("unexpected numPr of paragraph", i, numPr)
+		}
+	}
+	if l := n.Num(1).Level(0); l.NumFmt.Val != LIST_BULLET || l.LvlText.Val != "●" {
+		t.Fatal("unexpected bullet level", l.NumFmt, l.LvlText)
+	}
+	if l := n.Num(2).Level(1); l.NumFmt.Val != LIST_UPPER_ROMAN || l.LvlText.Val != "(%2)" {
+		t.Fatal("unexpected custom level", l.NumFmt, l.LvlText)
+	}
+	if l := n.Num(2).Level(2); l.NumFmt.Val != LIST_LOWER_ROMAN || l.ParagraphProperties.Ind.Left != 1260 {
+		t.Fatal("unexpected level 2", l.NumFmt, l.ParagraphProperties.Ind)
+	}
+	restarted := n.Num(3)
+	if restarted.AbstractNumID.Val != n.Num(2).AbstractNumID.Val || restarted.LvlOverrides[0].StartOverride.Val != 1 {
+		t.Fatal("unexpected restarted list")
+	}
+}
+
+func TestNumberingOrder(t *testing.T) {
+	w := New().WithDefaultTheme()
+	_, err := w.Numbering()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range w.docRelation.Relationship {
+		if r.Type == REL_NUMBERING {
+			t.Fatal("expected no relationship before writing")
+		}
+	}
+
+	var n Numbering
+	err = xml.Unmarshal(StringToBytes(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:numPicBullet w:numPicBulletId="0"/><w:abstractNum w:abstractNumId="0"><w:nsid w:val="1"/><w:multiLevelType w:val="singleLevel"/><w:tmpl w:val="2"/>`+
+		`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="a"/><w:lvlPicBulletId w:val="0"/><w:lvlJc w:val="left"/></w:lvl></w:abstractNum>`+
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num><w:numIdMacAtCleanup w:val="1"/></w:numbering>`), &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := xml.Marshal(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	for _, names := range [][2]string{
+		{"<w:numPicBullet", "<w:abstractNum "}, {"<w:nsid", "<w:multiLevelType"}, {"<w:multiLevelType", "<w:tmpl"},
+		{"<w:tmpl", "<w:lvl "}, {"<w:lvlText", "<w:lvlPicBulletId"}, {"<w:lvlPicBulletId", "<w:lvlJc"},
+		{"<w:num ", "<w:numIdMacAtCleanup"},
+	} {
+		i, j := strings.Index(s, names[0]), strings.Index(s, names[1])
+		if i < 0 || j < 0 || i > j {
+			t.Fatal("expected", names[0], "before", names[1], "in", s)
+		}
+	}
+}
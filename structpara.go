@@ -30,24 +30,38 @@ import (
 // ParagraphProperties <w:pPr>
 type ParagraphProperties struct {
 	XMLName        xml.Name `xml:"w:pPr,omitempty"`
+	Style          *Style
+	NumPr          *NumPr
 	Tabs           *Tabs
 	Spacing        *Spacing
 	Ind            *Ind
 	Justification  *Justification
 	Shade          *Shade
 	Kern           *Kern
-	Style          *Style
 	TextAlignment  *TextAlignment
 	AdjustRightInd *AdjustRightInd
 	SnapToGrid     *SnapToGrid
 	Kinsoku        *Kinsoku
 	OverflowPunct  *OverflowPunct
+	OutlineLvl     *OutlineLvl
+
+	Others []*RawXML // unsupported elements kept as is
 
 	RunProperties *RunProperties
+
+	SectPr *SectPr // the section ends at this paragraph
+
+	Change *ParagraphPropertiesChange
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (p *ParagraphProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, p, p.Others)
 }
 
 // UnmarshalXML ...
 func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -57,6 +71,8 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 			return err
 		}
 		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
 			case "tabs":
 				var value Tabs
@@ -106,8 +122,36 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 					return err
 				}
 				p.RunProperties = &value
+			case "sectPr":
+				var value SectPr
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				p.SectPr = &value
+			case "pPrChange":
+				var value ParagraphPropertiesChange
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				p.Change = &value
 			case "pStyle":
 				p.Style = &Style{Val: getAtt(tt.Attr, "val")}
+			case "numPr":
+				var value NumPr
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				p.NumPr = &value
+			case "outlineLvl":
+				var value OutlineLvl
+				value.Val, err = getAttInt(tt.Attr, "val")
+				if err != nil {
+					return err
+				}
+				p.OutlineLvl = &value
 			case "textAlignment":
 				p.TextAlignment = &TextAlignment{Val: getAtt(tt.Attr, "val")}
 			case "adjustRightInd":
@@ -155,11 +199,12 @@ func (p *ParagraphProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 				}
 				p.OverflowPunct = &value
 			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				continue
+				p.Others = append(p.Others, value.placeAfter(after))
 			}
 		}
 	}
@@ -175,6 +220,8 @@ type Paragraph struct {
 	// RsidRDefault string `xml:"w:rsidRDefault,attr,omitempty"`
 	// RsidP        string `xml:"w:rsidP,attr,omitempty"`
 
+	ParaID string `xml:"w14:paraId,attr,omitempty"` // ParaID links comments to commentsExtended
+
 	Properties *ParagraphProperties
 	Children   []interface{}
 
@@ -188,6 +235,14 @@ func (p *Paragraph) String() string {
 		case *Hyperlink:
 			id := o.ID
 			text := o.Run.InstrText
+			if o.Anchor != "" {
+				sb.WriteString("[")
+				sb.WriteString(text)
+				sb.WriteString("](#")
+				sb.WriteString(o.Anchor)
+				sb.WriteByte(')')
+				continue
+			}
 			link, err := p.file.ReferTarget(id)
 			sb.WriteString("[")
 			sb.WriteString(text)
@@ -198,6 +253,12 @@ func (p *Paragraph) String() string {
 				sb.WriteString(link)
 			}
 			sb.WriteByte(')')
+		case *SimpleField:
+			sb.WriteString((&Paragraph{Children: o.Children, file: p.file}).String())
+		case *Revision:
+			if o.IsInsertion() { // show the text as if the revision were accepted
+				sb.WriteString((&Paragraph{Children: o.Children, file: p.file}).String())
+			}
 		case *Run:
 			for _, c := range o.Children {
 				switch x := c.(type) {
@@ -226,7 +287,8 @@ func (p *Paragraph) String() string {
 }
 
 // UnmarshalXML ...
-func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (p *Paragraph) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	p.ParaID = getAtt(start.Attr, "paraId")
 	/*for _, attr := range start.Attr {
 		switch attr.Name.Local {
 		case "rsidR":
@@ -259,14 +321,8 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				id := getAtt(tt.Attr, "id")
-				anchor := getAtt(tt.Attr, "anchor")
-				if id != "" {
-					value.ID = id
-				}
-				if anchor != "" {
-					value.ID = anchor
-				}
+				value.ID = getAtt(tt.Attr, "id")
+				value.Anchor = getAtt(tt.Attr, "anchor")
 				elem = &value
 			case "r":
 				var value Run
@@ -291,12 +347,56 @@ func (p *Paragraph) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				}
 				p.Properties = &value
 				continue
-			default:
-				err = d.Skip() // skip unsupported tags
+			case "bookmarkStart":
+				var value BookmarkStart
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				elem = &value
+			case "bookmarkEnd":
+				var value BookmarkEnd
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				elem = &value
+			case "fldSimple":
+				value := SimpleField{file: p.file}
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				elem = &value
+			case "ins", "del", "moveFrom", "moveTo":
+				value := Revision{file: p.file}
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				elem = &value
+			case "commentRangeStart", "commentRangeEnd":
+				var id int
+				id, err = getAttInt(tt.Attr, "id")
 				if err != nil {
 					return err
 				}
-				continue
+				if tt.Name.Local == "commentRangeStart" {
+					elem = &CommentRangeStart{ID: id}
+				} else {
+					elem = &CommentRangeEnd{ID: id}
+				}
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+			default:
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				elem = &value
 			}
 			children = append(children, elem)
 		}
@@ -0,0 +1,75 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("a\tb\nc")
+	p.AddText(" hidden").RunProperties.Vanish = &Vanish{}
+	w.AddParagraph().AddPageBreaks()
+	tbl := w.AddTable(1, 2)
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("x")
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("y")
+	tbl.TableRows[0].TableCells[1].AddParagraph().AddText("z")
+	p = w.AddParagraph()
+	p.AddText("pic:")
+	r, err := p.AddInlineDrawingFrom("testdata/fumiama.JPG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Children[0].(*Drawing).Inline.DocPr.Descr = "avatar"
+	r = w.AddParagraph().AddInlineShape(100, 100, "box", "auto", "rect", nil)
+	r.Children[0].(*Drawing).Inline.Graphic.GraphicData.Shape.TextBox = &WPSTextBox{
+		Content: &WTextBoxContent{Paragraphs: []Paragraph{{Children: []interface{}{
+			&Run{Children: []interface{}{&Text{Text: "in box"}}},
+		}, file: w}}},
+	}
+	w.AddHeader().AddParagraph().AddText("head")
+
+	s := w.PlainText(PlainTextOptions{})
+	if s != "a\tb\nc hidden\n\f\nx\ny\tz\npic:\nin box\n\n" {
+		t.Fatalf("unexpected text %q", s)
+	}
+	s = w.PlainText(PlainTextOptions{CellSeparator: " | ", AltText: true, SkipHidden: true, HeadersFooters: true})
+	if s != "a\tb\nc\n\f\nx\ny | z\npic:avatar\nin box\n\nhead\n" {
+		t.Fatalf("unexpected text %q", s)
+	}
+
+	// alt text and vanish survive a round trip
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2 := w.PlainText(PlainTextOptions{CellSeparator: " | ", AltText: true, SkipHidden: true, HeadersFooters: true}); s2 != s {
+		t.Fatalf("expected %q but has %q", s, s2)
+	}
+}
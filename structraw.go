@@ -0,0 +1,469 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	XMLNS_XML  = `http://www.w3.org/XML/1998/namespace`
+	XMLNS_M    = `http://schemas.openxmlformats.org/officeDocument/2006/math`
+	XMLNS_W10  = `urn:schemas-microsoft-com:office:word`
+	XMLNS_W14  = `http://schemas.microsoft.com/office/word/2010/wordml`
+	XMLNS_W15  = `http://schemas.microsoft.com/office/word/2012/wordml`
+	XMLNS_WP14 = `http://schemas.microsoft.com/office/word/2010/wordprocessingDrawing`
+	XMLNS_WNE  = `http://schemas.microsoft.com/office/word/2006/wordml`
+	XMLNS_WPI  = `http://schemas.microsoft.com/office/word/2010/wordprocessingInk`
+
+	XMLNS_W16      = `http://schemas.microsoft.com/office/word/2018/wordml`
+	XMLNS_W16CEX   = `http://schemas.microsoft.com/office/word/2018/wordml/cex`
+	XMLNS_W16CID   = `http://schemas.microsoft.com/office/word/2016/wordml/cid`
+	XMLNS_W16DU    = `http://schemas.microsoft.com/office/word/2023/wordml/word16du`
+	XMLNS_W16SDTDH = `http://schemas.microsoft.com/office/word/2020/wordml/sdtdatahash`
+	XMLNS_W16SE    = `http://schemas.microsoft.com/office/word/2015/wordml/symex`
+)
+
+// knownPrefixes maps the well-known namespaces to the prefixes Word uses
+var knownPrefixes = map[string]string{
+	XMLNS_W:                 "w",
+	XMLNS_R:                 "r",
+	XMLNS_WP:                "wp",
+	XMLNS_WPS:               "wps",
+	XMLNS_WPC:               "wpc",
+	XMLNS_WPG:               "wpg",
+	XMLNS_MC:                "mc",
+	XMLNS_O:                 "o",
+	XMLNS_V:                 "v",
+	XMLNS_M:                 "m",
+	XMLNS_W10:               "w10",
+	XMLNS_W14:               "w14",
+	XMLNS_W15:               "w15",
+	XMLNS_WP14:              "wp14",
+	XMLNS_WNE:               "wne",
+	XMLNS_WPI:               "wpi",
+	XMLNS_W16:               "w16",
+	XMLNS_W16CEX:            "w16cex",
+	XMLNS_W16CID:            "w16cid",
+	XMLNS_W16DU:             "w16du",
+	XMLNS_W16SDTDH:          "w16sdtdh",
+	XMLNS_W16SE:             "w16se",
+	XMLNS_DRAWINGML_MAIN:    "a",
+	XMLNS_DRAWINGML_PICTURE: "pic",
+	XMLNS_VT:                "vt",
+}
+
+// RawXML is an element that is not supported yet. It keeps the
+// whole element as it is and writes it back on saving, so that
+// nothing will be lost after a Parse-WriteTo round trip.
+type RawXML struct {
+	tokens []xml.Token
+
+	// after is the local name of the previous sibling on decoding,
+	// so that the element can be written back to the same position
+	after    string
+	hasAfter bool
+
+	// space is the default namespace of the part, in which
+	// the names are written without prefix
+	space string
+}
+
+// placeAfter records that r follows the sibling of local name prev,
+// or is the first child if prev is empty
+func (r *RawXML) placeAfter(prev string) *RawXML {
+	r.after, r.hasAfter = prev, true
+	return r
+}
+
+// clone deep-copies the element
+func (r *RawXML) clone() *RawXML {
+	n := *r
+	n.tokens = make([]xml.Token, len(r.tokens))
+	for i, t := range r.tokens {
+		n.tokens[i] = xml.CopyToken(t)
+	}
+	return &n
+}
+
+// Name of the element with its namespace URL in Space
+func (r *RawXML) Name() xml.Name {
+	if len(r.tokens) == 0 {
+		return xml.Name{}
+	}
+	return r.tokens[0].(xml.StartElement).Name
+}
+
+// String returns all the char data in the element
+func (r *RawXML) String() string {
+	sb := strings.Builder{}
+	for _, t := range r.tokens {
+		if cd, ok := t.(xml.CharData); ok {
+			sb.Write(cd)
+		}
+	}
+	return sb.String()
+}
+
+// UnmarshalXML ...
+func (r *RawXML) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	r.tokens = append(make([]xml.Token, 0, 16), start.Copy())
+	for depth := 1; depth > 0; {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			depth++
+			r.tokens = append(r.tokens, tt.Copy())
+		case xml.EndElement:
+			depth--
+			r.tokens = append(r.tokens, tt)
+		case xml.CharData:
+			r.tokens = append(r.tokens, tt.Copy())
+		case xml.Comment:
+			r.tokens = append(r.tokens, tt.Copy())
+		default:
+			// ProcInst and Directive cannot appear inside
+		}
+	}
+	return nil
+}
+
+// MarshalXML writes the element back with its original namespaces.
+//
+// Namespaces other than w, r and the default ones are declared on the element itself
+// because the root of the part may not declare them. The prefixes declared in the
+// source are kept, so that mc:Ignorable and mc:Choice Requires still refer to them.
+func (r *RawXML) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	if len(r.tokens) == 0 {
+		return nil
+	}
+	declared := make(map[string]string, 8) // prefix -> namespace in the source
+	for _, t := range r.tokens {
+		if tt, ok := t.(xml.StartElement); ok {
+			for _, a := range tt.Attr {
+				if _, ok := declared[a.Name.Local]; a.Name.Space == "xmlns" && !ok {
+					declared[a.Name.Local] = a.Value
+				}
+			}
+		}
+	}
+	prefixes := make(map[string]string, 8)
+	taken := make(map[string]bool, 8)
+	decls := make([]xml.Attr, 0, 8)
+	declare := func(space, p string) {
+		prefixes[space] = p
+		taken[p] = true
+		if p == "" || p == "w" || p == "r" {
+			return
+		}
+		decls = append(decls, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: space})
+	}
+	use := func(n xml.Name) {
+		if n.Space == "" || n.Space == XMLNS_XML || n.Space == "xmlns" {
+			return
+		}
+		if _, ok := prefixes[n.Space]; ok {
+			return
+		}
+		if n.Space == r.space {
+			prefixes[n.Space] = ""
+			return
+		}
+		p, ok := "", false
+		for dp, space := range declared {
+			if space == n.Space && !taken[dp] && (!ok || dp < p) {
+				p, ok = dp, true
+			}
+		}
+		if !ok {
+			p, ok = knownPrefixes[n.Space]
+		}
+		for i := 0; !ok || (p != "" && taken[p]); i++ {
+			p, ok = "ns"+strconv.Itoa(i), true
+		}
+		declare(n.Space, p)
+	}
+	for _, t := range r.tokens {
+		if tt, ok := t.(xml.StartElement); ok {
+			use(tt.Name)
+			for _, a := range tt.Attr {
+				use(a.Name)
+				if (a.Name.Space != XMLNS_MC && (a.Name.Space != "" || tt.Name.Space != XMLNS_MC)) ||
+					(a.Name.Local != "Requires" && a.Name.Local != "Ignorable") {
+					continue
+				}
+				// the prefixes in the value must be declared even if not used
+				for _, p := range strings.Fields(a.Value) {
+					if space, ok := declared[p]; ok && !taken[p] {
+						if _, ok := prefixes[space]; !ok {
+							declare(space, p)
+						}
+					}
+				}
+			}
+		}
+	}
+	conv := func(n xml.Name) xml.Name {
+		switch n.Space {
+		case "":
+			return n
+		case XMLNS_XML:
+			return xml.Name{Local: "xml:" + n.Local}
+		default:
+			if prefixes[n.Space] == "" {
+				return xml.Name{Local: n.Local}
+			}
+			return xml.Name{Local: prefixes[n.Space] + ":" + n.Local}
+		}
+	}
+	for i, t := range r.tokens {
+		switch tt := t.(type) {
+		case xml.StartElement:
+			nt := xml.StartElement{Name: conv(tt.Name), Attr: make([]xml.Attr, 0, len(tt.Attr)+len(decls))}
+			if i == 0 {
+				nt.Attr = append(nt.Attr, decls...)
+			}
+			for _, a := range tt.Attr {
+				if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue // namespaces are re-declared above
+				}
+				nt.Attr = append(nt.Attr, xml.Attr{Name: conv(a.Name), Value: a.Value})
+			}
+			t = nt
+		case xml.EndElement:
+			t = xml.EndElement{Name: conv(tt.Name)}
+		}
+		err := e.EncodeToken(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sdtContent returns the indices of the start and the end tokens of
+// <w:sdtContent> if r is a content control <w:sdt>, or -1 if not
+func (r *RawXML) sdtContent() (from, to int) {
+	if n := r.Name(); n.Space != XMLNS_W || n.Local != "sdt" {
+		return -1, -1
+	}
+	from, depth := -1, 0
+	for i, t := range r.tokens {
+		switch tt := t.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && tt.Name.Space == XMLNS_W && tt.Name.Local == "sdtContent" {
+				from = i
+			}
+		case xml.EndElement:
+			if depth == 2 && from >= 0 {
+				return from, i
+			}
+			depth--
+		}
+	}
+	return -1, -1
+}
+
+// setContent replaces the tokens between the start token of index from
+// and the end token of index to with the encoded items
+func (r *RawXML) setContent(from, to int, items []interface{}) error {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	start := xml.StartElement{Name: xml.Name{Local: "content"}}
+	for space, p := range knownPrefixes {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: space})
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		err = e.Encode(it)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.EncodeToken(start.End())
+	if err != nil {
+		return err
+	}
+	err = e.Flush()
+	if err != nil {
+		return err
+	}
+	var content RawXML
+	err = xml.NewDecoder(&buf).Decode(&content)
+	if err != nil {
+		return err
+	}
+	tokens := make([]xml.Token, 0, from+1+len(content.tokens)+len(r.tokens)-to)
+	tokens = append(tokens, r.tokens[:from+1]...)
+	tokens = append(tokens, content.tokens[1:len(content.tokens)-1]...)
+	r.tokens = append(tokens, r.tokens[to:]...)
+	return nil
+}
+
+// tokenSliceReader feeds saved tokens into xml.NewTokenDecoder
+type tokenSliceReader []xml.Token
+
+// Token implements xml.TokenReader
+func (r *tokenSliceReader) Token() (xml.Token, error) {
+	if len(*r) == 0 {
+		return nil, io.EOF
+	}
+	t := (*r)[0]
+	*r = (*r)[1:]
+	return t, nil
+}
+
+// marshalInOrder writes v, a pointer to a struct of properties, like the
+// default marshaller does, but puts each of the unsupported elements in
+// others back after its previous sibling on decoding. The elements added
+// by hand are written at the position of the others field.
+func marshalInOrder(e *xml.Encoder, start xml.StartElement, v interface{}, others []*RawXML) error {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	if name := elementName(rt); name != "" {
+		// a marshaller is named by the field or the type instead of XMLName
+		start.Name = xml.Name{Local: name}
+	}
+	start.Attr = start.Attr[:0]
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get("xml"), ",")
+		if !strings.Contains(opts, "attr") || !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: fmt.Sprint(fv.Interface())})
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	written := make([]bool, len(others))
+	var writeAfter func(prev string) error
+	writeAfter = func(prev string) error {
+		for j, o := range others {
+			if written[j] || !o.hasAfter || o.after != prev {
+				continue
+			}
+			written[j] = true
+			err := e.Encode(o)
+			if err != nil {
+				return err
+			}
+			err = writeAfter(o.Name().Local)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = writeAfter("")
+	if err != nil {
+		return err
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, opts, _ := strings.Cut(sf.Tag.Get("xml"), ",")
+		if sf.Name == "XMLName" || name == "-" || strings.Contains(opts, "attr") || !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if sf.Name == "Others" {
+			for j, o := range others {
+				if !o.hasAfter {
+					written[j] = true
+					err = e.Encode(o)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if fv.IsZero() || (fv.Kind() == reflect.Slice && fv.Len() == 0) {
+			continue
+		}
+		if name != "" {
+			err = e.EncodeElement(fv.Interface(), xml.StartElement{Name: xml.Name{Local: name}})
+		} else {
+			err = e.Encode(fv.Interface())
+			name = elementName(sf.Type)
+		}
+		if err != nil {
+			return err
+		}
+		if i := strings.IndexByte(name, ':'); i >= 0 {
+			name = name[i+1:]
+		}
+		err = writeAfter(name)
+		if err != nil {
+			return err
+		}
+	}
+	// the previous siblings have been removed
+	for j, o := range others {
+		if !written[j] {
+			err = e.Encode(o)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// elementName is the name in the XMLName tag of typ
+// or of the elements of typ if it is a pointer or a slice
+func elementName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return ""
+	}
+	sf, ok := typ.FieldByName("XMLName")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("xml"), ",")
+	return name
+}
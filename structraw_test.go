@@ -0,0 +1,122 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const rawxml_doc = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:x="urn:example:unknown" xmlns:y="urn:example:other"><w:body>` +
+	`<w:bookmarkStart w:id="0" w:name="top"/>` +
+	`<w:p><w:proofErr w:type="spellStart"/><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:t xml:space="preserve">a </w:t></w:r><m:oMath><m:r><m:t>x</m:t></m:r></m:oMath>` +
+	`<w:r><mc:AlternateContent><mc:Choice Requires="x y"><x:thing x:a="1"/></mc:Choice><mc:Fallback><w:t>fb</w:t></mc:Fallback></mc:AlternateContent></w:r></w:p>` +
+	`<w:bookmarkEnd w:id="0"/>` +
+	`</w:body></w:document>`
+
+func TestRawXMLRoundTrip(t *testing.T) {
+	w := New()
+	err := newPartDecoder(strings.NewReader(rawxml_doc)).Decode(&w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Document.Body.Items) != 3 {
+		t.Fatal("expected 3 body items but has", len(w.Document.Body.Items))
+	}
+	if w.Document.Body.Items[0].(*BookmarkStart).Name != "top" {
+		t.Fatal("expected bookmarkStart but has", w.Document.Body.Items[0])
+	}
+	var buf1, buf2 bytes.Buffer
+	_, err = marshaller{data: &w.Document}.WriteTo(&buf1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<w:bookmarkStart w:id="0" w:name="top"></w:bookmarkStart>`,
+		`<w:proofErr w:type="spellStart"></w:proofErr>`,
+		`<w:fldChar w:fldCharType="begin"></w:fldChar>`,
+		`<m:oMath xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"><m:r><m:t>x</m:t></m:r></m:oMath>`,
+		`xmlns:x="urn:example:unknown" xmlns:y="urn:example:other"><mc:Choice Requires="x y"><x:thing x:a="1"></x:thing></mc:Choice>`,
+		`<w:t>fb</w:t>`,
+		`<w:bookmarkEnd w:id="0"></w:bookmarkEnd>`,
+	} {
+		if !strings.Contains(buf1.String(), s) {
+			t.Fatal("expected", s, "in", buf1.String())
+		}
+	}
+	w = New()
+	err = newPartDecoder(bytes.NewReader(buf1.Bytes())).Decode(&w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = marshaller{data: &w.Document}.WriteTo(&buf2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf1.String() != buf2.String() {
+		t.Fatal("expected", buf1.String(), "but has", buf2.String())
+	}
+}
+
+func TestRawXMLOrder(t *testing.T) {
+	var pp ParagraphProperties
+	err := xml.Unmarshal(StringToBytes(`<w:pPr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:pStyle w:val="a"/><w:keepNext/><w:widowControl/><w:numPr><w:numId w:val="1"/></w:numPr><w:pBdr><w:top w:val="single"/></w:pBdr>`+
+		`<w:spacing w:after="0"/><w:contextualSpacing/><w:outlineLvl w:val="0"/><w:rPr><w:b/></w:rPr></w:pPr>`), &pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp.Others = append(pp.Others, &RawXML{tokens: []xml.Token{
+		xml.StartElement{Name: xml.Name{Space: XMLNS_W, Local: "bidi"}}, xml.EndElement{Name: xml.Name{Space: XMLNS_W, Local: "bidi"}},
+	}})
+	b, err := xml.Marshal(&pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	for _, names := range [][2]string{
+		{"<w:pStyle", "<w:keepNext>"}, {"<w:keepNext>", "<w:widowControl>"}, {"<w:widowControl>", "<w:numPr>"},
+		{"<w:numPr>", "<w:pBdr>"}, {"<w:pBdr>", "<w:spacing"}, {"<w:spacing", "<w:contextualSpacing>"},
+		{"<w:contextualSpacing>", "<w:outlineLvl"}, {"<w:outlineLvl", "<w:bidi>"}, {"<w:bidi>", "<w:rPr>"},
+	} {
+		i, j := strings.Index(s, names[0]), strings.Index(s, names[1])
+		if i < 0 || j < 0 || i > j {
+			t.Fatal("expected", names[0], "before", names[1], "in", s)
+		}
+	}
+
+	var sd StyleDefinition
+	err = xml.Unmarshal(StringToBytes(`<w:style xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" w:type="paragraph" w:styleId="a">`+
+		`<w:name w:val="A"/><w:aliases w:val="b"/><w:basedOn w:val="c"/><w:autoRedefine/><w:hidden/><w:uiPriority w:val="1"/><w:qFormat/><w:rsid w:val="00"/><w:rPr><w:b/></w:rPr></w:style>`), &sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = xml.Marshal(&sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = string(b)
+	if !strings.HasPrefix(s, `<w:style w:type="paragraph" w:styleId="a"><w:name w:val="A"></w:name><w:aliases w:val="b"></w:aliases><w:basedOn w:val="c"></w:basedOn><w:autoRedefine></w:autoRedefine><w:hidden></w:hidden><w:uiPriority w:val="1"></w:uiPriority><w:qFormat></w:qFormat><w:rsid w:val="00"></w:rsid><w:rPr>`) {
+		t.Fatal("unexpected order", s)
+	}
+}
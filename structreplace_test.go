@@ -0,0 +1,162 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReplaceText(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddText("Hello {{")
+	p.AddText("na").Bold()
+	p.AddBookmark("mark")
+	p.AddText("me}} and {{name}}!\t{{x}}")
+	tbl := w.AddTable(1, 1)
+	tbl.TableRows[0].TableCells[0].AddParagraph().AddText("cell {{name}}")
+	r := w.AddParagraph().AddInlineShape(100, 100, "box", "auto", "rect", nil)
+	r.Children[0].(*Drawing).Inline.Graphic.GraphicData.Shape.TextBox = &WPSTextBox{
+		Content: &WTextBoxContent{Paragraphs: []Paragraph{{Children: []interface{}{
+			&Run{Children: []interface{}{&Text{Text: "box {{na"}}},
+			&Run{Children: []interface{}{&Text{Text: "me}}"}}},
+		}, file: w}}},
+	}
+	w.AddHeader().AddParagraph().AddText("{{name}} header")
+	lp := w.AddParagraph()
+	lp.AddText("see {{na")
+	link := &Hyperlink{Anchor: "mark", Run: Run{Children: []interface{}{&Text{Text: "me}} {{name}}"}}}}
+	lp.Children = append(lp.Children, link)
+
+	n := w.ReplaceText("{{name}}", "Bob")
+	if n != 6 {
+		t.Fatal("expected 6 replacements but has", n)
+	}
+	if s := runText(&link.Run); s != "me}} Bob" {
+		t.Fatal("expected the text of the link replaced alone but has", s)
+	}
+	if p.String() != "Hello Bob and Bob!\t{{x}}" {
+		t.Fatal("unexpected paragraph", p.String())
+	}
+	if len(p.Children) != 4 {
+		t.Fatal("expected the emptied bold run to be removed but has", len(p.Children), "children")
+	}
+	if p.Children[1].(*Run).RunProperties.Bold != nil || runText(p.Children[1].(*Run)) != "Hello Bob" {
+		t.Fatal("expected the replaced text in the first run but has", p.Children[1])
+	}
+	if s := tbl.TableRows[0].TableCells[0].Paragraphs()[0].String(); s != "cell Bob" {
+		t.Fatal("unexpected cell", s)
+	}
+	if s := w.headers[0].Items[0].(*Paragraph).String(); s != "Bob header" {
+		t.Fatal("unexpected header", s)
+	}
+
+	n = w.ReplaceRegexp(regexp.MustCompile(`\{\{(\w+)\}\}`), "<$1>\n")
+	if n != 1 || p.String() != "Hello Bob and Bob!\t<x>\n" {
+		t.Fatal("unexpected paragraph", n, p.String())
+	}
+	n = p.ReplaceText("!\t<", "?")
+	if n != 1 || p.String() != "Hello Bob and Bob?x>\n" {
+		t.Fatal("unexpected paragraph", n, p.String())
+	}
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	box := w.Document.Body.Items[2].(*Paragraph).Children[0].(*Run).Children[0].(*Drawing).Inline.Graphic.GraphicData.Shape.TextBox
+	if s := box.Content.Paragraphs[0].String(); s != "box Bob" {
+		t.Fatal("unexpected text box", s)
+	}
+}
+
+const replace_doc = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	`<w:p><w:r><w:t xml:space="preserve">Hi {{</w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:t>name</w:t></w:r>` +
+	`<w:proofErr w:type="spellEnd"/><w:r><w:t>}}</w:t></w:r></w:p>` +
+	`<w:p><w:ins w:id="1" w:author="a"><w:r><w:t>ins {{name}}</w:t></w:r></w:ins>` +
+	`<w:del w:id="2" w:author="a"><w:r><w:delText>{{name}}</w:delText></w:r></w:del>` +
+	`<w:fldSimple w:instr="AUTHOR"><w:r><w:t>fld {{name}}</w:t></w:r></w:fldSimple>` +
+	`<w:sdt><w:sdtPr><w:alias w:val="inline"/></w:sdtPr><w:sdtContent><w:r><w:t>sdt {{name}}</w:t></w:r></w:sdtContent></w:sdt></w:p>` +
+	`<w:sdt><w:sdtPr><w:alias w:val="block"/></w:sdtPr><w:sdtContent><w:p><w:r><w:t>block {{name}}</w:t></w:r></w:p></w:sdtContent></w:sdt>` +
+	`</w:body></w:document>`
+
+func TestReplaceTextInContainers(t *testing.T) {
+	w := New()
+	err := xml.Unmarshal(StringToBytes(replace_doc), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := w.ReplaceText("{{name}}", "Bob")
+	if n != 5 {
+		t.Fatal("expected 5 replacements but has", n)
+	}
+	p := w.Document.Body.Items[0].(*Paragraph)
+	if len(p.Children) != 1 || runText(p.Children[0].(*Run)) != "Hi Bob" {
+		t.Fatal("expected the runs merged by MergeText but has", len(p.Children), p.String())
+	}
+
+	var buf bytes.Buffer
+	_, err = marshaller{data: &w.Document}.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<w:ins w:id="1" w:author="a"><w:r><w:t xml:space="preserve">ins </w:t><w:t>Bob</w:t></w:r></w:ins>`,
+		`<w:delText>{{name}}</w:delText>`,
+		`<w:fldSimple w:instr="AUTHOR"><w:r><w:t xml:space="preserve">fld </w:t><w:t>Bob</w:t></w:r></w:fldSimple>`,
+		`<w:sdtContent><w:r><w:t xml:space="preserve">sdt </w:t><w:t>Bob</w:t></w:r></w:sdtContent>`,
+		`<w:sdtContent><w:p><w:r><w:t xml:space="preserve">block </w:t><w:t>Bob</w:t></w:r></w:p></w:sdtContent>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatal("expected", s, "in", buf.String())
+		}
+	}
+}
+
+func TestReplaceRegexpBreaksAndEmptyMatches(t *testing.T) {
+	w := New()
+	p := w.AddParagraph()
+	p.AddText("a")
+	p.AddPageBreaks()
+	p.AddText("b")
+	if n := p.ReplaceRegexp(regexp.MustCompile(`a\nb|\n`), ""); n != 0 || len(p.Children) != 3 {
+		t.Fatal("expected the break kept but has", n, len(p.Children))
+	}
+
+	for _, c := range []struct{ re, s string }{{`^|x`, "x"}, {`x*`, "ab"}, {`b*`, "abc"}} {
+		re := regexp.MustCompile(c.re)
+		p := w.AddParagraph()
+		p.AddText(c.s)
+		n := p.ReplaceRegexp(re, "Q")
+		if expected := re.ReplaceAllString(c.s, "Q"); p.String() != expected || n != len(re.FindAllStringIndex(c.s, -1)) {
+			t.Fatal("expected", expected, "but has", p.String(), n)
+		}
+	}
+}
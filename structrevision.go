@@ -0,0 +1,216 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	REVISION_INS       = "ins"
+	REVISION_DEL       = "del"
+	REVISION_MOVE_FROM = "moveFrom"
+	REVISION_MOVE_TO   = "moveTo"
+)
+
+// Revision <w:ins> <w:del> <w:moveFrom> <w:moveTo> is a tracked change
+// of the runs inside it
+//
+//	XMLName.Local 的取值可以是以下之一：
+//		w:ins：插入的内容。
+//		w:del：删除的内容，文本在 w:delText 中。
+//		w:moveFrom：移动前的内容，同删除。
+//		w:moveTo：移动后的内容，同插入。
+type Revision struct {
+	XMLName xml.Name
+	ID      int    `xml:"w:id,attr"`
+	Author  string `xml:"w:author,attr,omitempty"`
+	Date    string `xml:"w:date,attr,omitempty"`
+
+	// Children are *Run, *Hyperlink, nested *Revision and *RawXML like Paragraph.Children
+	Children []interface{}
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (rv *Revision) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	rv.XMLName = xml.Name{Local: "w:" + start.Name.Local}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "id":
+			rv.ID, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+		case "author":
+			rv.Author = attr.Value
+		case "date":
+			rv.Date = attr.Value
+		default:
+			// ignore other attributes
+		}
+	}
+	p := Paragraph{file: rv.file}
+	err = p.UnmarshalXML(d, xml.StartElement{Name: start.Name})
+	rv.Children = p.Children
+	return
+}
+
+// Kind returns one of REVISION_INS, REVISION_DEL, REVISION_MOVE_FROM and REVISION_MOVE_TO
+func (rv *Revision) Kind() string {
+	return strings.TrimPrefix(rv.XMLName.Local, "w:")
+}
+
+// IsInsertion reports whether the content of rv is shown after accepting it
+func (rv *Revision) IsInsertion() bool {
+	k := rv.Kind()
+	return k == REVISION_INS || k == REVISION_MOVE_TO
+}
+
+// RevisionMark <w:ins> <w:del> in the run properties of a paragraph mark
+// tells that the paragraph mark is inserted or deleted
+type RevisionMark struct {
+	ID     int    `xml:"w:id,attr"`
+	Author string `xml:"w:author,attr,omitempty"`
+	Date   string `xml:"w:date,attr,omitempty"`
+}
+
+// UnmarshalXML ...
+func (m *RevisionMark) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	m.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	m.Author = getAtt(start.Attr, "author")
+	m.Date = getAtt(start.Attr, "date")
+	return d.Skip()
+}
+
+// DelText <w:delText> is the text in a deleted run
+type DelText struct {
+	XMLName  xml.Name `xml:"w:delText,omitempty"`
+	XMLSpace string   `xml:"xml:space,attr,omitempty"`
+
+	Text string `xml:",chardata"`
+}
+
+// UnmarshalXML ...
+func (r *DelText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var t Text
+	err := t.UnmarshalXML(d, start)
+	r.XMLSpace = t.XMLSpace
+	r.Text = t.Text
+	return err
+}
+
+// RunPropertiesChange <w:rPrChange> keeps the run properties before the change
+type RunPropertiesChange struct {
+	XMLName xml.Name `xml:"w:rPrChange,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+	Author  string   `xml:"w:author,attr,omitempty"`
+	Date    string   `xml:"w:date,attr,omitempty"`
+
+	RunProperties *RunProperties
+}
+
+// UnmarshalXML ...
+func (c *RunPropertiesChange) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	c.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	c.Author = getAtt(start.Attr, "author")
+	c.Date = getAtt(start.Attr, "date")
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "rPr" {
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			var value RunProperties
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			c.RunProperties = &value
+		}
+	}
+	return nil
+}
+
+// ParagraphPropertiesChange <w:pPrChange> keeps the paragraph properties before the change
+type ParagraphPropertiesChange struct {
+	XMLName xml.Name `xml:"w:pPrChange,omitempty"`
+	ID      int      `xml:"w:id,attr"`
+	Author  string   `xml:"w:author,attr,omitempty"`
+	Date    string   `xml:"w:date,attr,omitempty"`
+
+	Properties *ParagraphProperties
+}
+
+// UnmarshalXML ...
+func (c *ParagraphPropertiesChange) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	c.ID, err = getAttInt(start.Attr, "id")
+	if err != nil {
+		return
+	}
+	c.Author = getAtt(start.Attr, "author")
+	c.Date = getAtt(start.Attr, "date")
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "pPr" {
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			var value ParagraphProperties
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			c.Properties = &value
+		}
+	}
+	return nil
+}
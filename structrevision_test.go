@@ -0,0 +1,237 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const revision_doc = `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	`<w:p><w:pPr><w:jc w:val="center"/><w:rPr><w:del w:id="1" w:author="a" w:date="2023-01-01T00:00:00Z"/></w:rPr>` +
+	`<w:pPrChange w:id="2" w:author="a"><w:pPr><w:jc w:val="left"/></w:pPr></w:pPrChange></w:pPr>` +
+	`<w:r><w:t xml:space="preserve">keep </w:t></w:r>` +
+	`<w:ins w:id="3" w:author="a"><w:r><w:t>new</w:t></w:r></w:ins>` +
+	`<w:del w:id="4" w:author="a"><w:r><w:delText>old</w:delText></w:r></w:del></w:p>` +
+	`<w:p><w:r><w:rPr><w:b/><w:rPrChange w:id="5" w:author="a"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr><w:t> next</w:t></w:r></w:p>` +
+	`</w:body></w:document>`
+
+func parseRevisionDoc(t *testing.T) *Docx {
+	w := New()
+	err := xml.Unmarshal(StringToBytes(revision_doc), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestRevisions(t *testing.T) {
+	w := parseRevisionDoc(t)
+	if len(w.Revisions()) != 2 {
+		t.Fatal("expected 2 revisions but has", len(w.Revisions()))
+	}
+	p := w.Document.Body.Items[0].(*Paragraph)
+	if s := p.String(); s != "keep new" {
+		t.Fatalf("unexpected text %q", s)
+	}
+	if p.Properties.Change == nil || p.Properties.RunProperties.Del == nil {
+		t.Fatal("expected pPrChange and deleted paragraph mark")
+	}
+	var buf bytes.Buffer
+	_, err := marshaller{data: &w.Document}.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = New()
+	err = xml.Unmarshal(buf.Bytes(), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.AcceptRevision(4) || w.AcceptRevision(4) {
+		t.Fatal("expected revision 4 to be accepted once")
+	}
+	if n := w.AcceptAllRevisions(); n != 4 {
+		t.Fatal("expected 4 revisions but has", n)
+	}
+	if len(w.Document.Body.Items) != 1 {
+		t.Fatal("expected paragraphs joined")
+	}
+	p = w.Document.Body.Items[0].(*Paragraph)
+	if s := p.String(); s != "keep new next" {
+		t.Fatalf("unexpected accepted text %q", s)
+	}
+	if p.Children[2].(*Run).RunProperties.Bold == nil {
+		t.Fatal("expected bold")
+	}
+
+	w = parseRevisionDoc(t)
+	if n := w.RejectAllRevisions(); n != 5 {
+		t.Fatal("expected 5 revisions but has", n)
+	}
+	if len(w.Document.Body.Items) != 2 {
+		t.Fatal("expected paragraphs kept")
+	}
+	p = w.Document.Body.Items[0].(*Paragraph)
+	if s := p.String(); s != "keep old" || p.Properties.Justification.Val != "left" {
+		t.Fatalf("unexpected rejected paragraph %q", s)
+	}
+	rp := w.Document.Body.Items[1].(*Paragraph).Children[0].(*Run).RunProperties
+	if rp.Bold != nil || rp.Italic == nil {
+		t.Fatal("expected the old run properties")
+	}
+}
+
+func TestTrackChanges(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	r := p.AddText("remove me")
+	p.AddText(" stay")
+	p.AddInsertedText(" added", "bob")
+	rv := p.TrackDeletion(r, "bob")
+	if rv == nil || rv.ID != 1 || rv.Kind() != REVISION_DEL {
+		t.Fatal("unexpected deletion", rv)
+	}
+	if s := p.String(); s != " stay added" {
+		t.Fatalf("unexpected text %q", s)
+	}
+	if !w.RejectRevision(rv.ID) {
+		t.Fatal("expected revision", rv.ID)
+	}
+	if s := p.String(); s != "remove me stay added" {
+		t.Fatalf("unexpected rejected text %q", s)
+	}
+	if _, ok := r.Children[0].(*Text); !ok {
+		t.Fatal("expected w:t after rejecting deletion")
+	}
+}
+
+func TestRevisionIDSeed(t *testing.T) {
+	w := parseRevisionDoc(t)
+	w.Document.Body.file = w
+	p := w.Document.Body.Items[1].(*Paragraph)
+	p.Children = append(p.Children, &RawXML{tokens: []xml.Token{
+		xml.StartElement{Name: xml.Name{Space: XMLNS_W, Local: "moveToRangeStart"}, Attr: []xml.Attr{{Name: xml.Name{Space: XMLNS_W, Local: "id"}, Value: "9"}}},
+		xml.EndElement{Name: xml.Name{Space: XMLNS_W, Local: "moveToRangeStart"}},
+	}})
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = w.Document.Body.Items[1].(*Paragraph)
+	for i := 10; i < 12; i++ {
+		p.AddInsertedText("x", "b")
+		if rv := p.Children[len(p.Children)-1].(*Revision); rv.ID != i {
+			t.Fatal("expected revision id", i, "but has", rv.ID)
+		}
+	}
+}
+
+func TestCopyRevisions(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	p.AddLink("home", "https://example.com")
+	_, err := p.AddInlineDrawingFrom("testdata/fumiamayoko.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AddBookmark("chap")
+	p.AddFootnote("a note")
+	p.Children = []interface{}{&Revision{
+		XMLName:  xml.Name{Local: "w:ins"},
+		ID:       7,
+		Author:   "a",
+		Children: p.Children,
+		file:     w,
+	}}
+
+	nw := New().WithDefaultTheme()
+	nw.AddParagraph().AddBookmark("chap")
+	nw.AddParagraph().AddFootnote("existing")
+	nw.AppendFile(w)
+	var buf bytes.Buffer
+	_, err = nw.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nw, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv := nw.Document.Body.Items[2].(*Paragraph).Children[0].(*Revision)
+	if tgt, err := nw.ReferTarget(rv.Children[1].(*Hyperlink).ID); err != nil || tgt != "https://example.com" {
+		t.Fatal("unexpected link target", tgt, err)
+	}
+	d := rv.Children[2].(*Run).Children[0].(*Drawing)
+	if tgt, err := nw.ReferTarget(d.Inline.Graphic.GraphicData.Pic.BlipFill.Blip.Embed); err != nil || tgt != "media/image1.png" {
+		t.Fatal("unexpected image target", tgt, err)
+	}
+	if b := rv.Children[0].(*BookmarkStart); b.Name != "chap_1" {
+		t.Fatal("expected the copied bookmark renamed but has", b.Name)
+	}
+	ref := rv.Children[4].(*Run).Children[0].(*FootnoteReference)
+	if s := nw.Footnotes().Note(ref.ID).String(); s != " a note" {
+		t.Fatal("unexpected copied footnote", ref.ID, s)
+	}
+}
+
+func TestRevisionsInParts(t *testing.T) {
+	w := New().WithDefaultTheme()
+	p := w.AddParagraph()
+	r := p.AddText("body")
+	w.AddHeader().AddParagraph().AddInsertedText("header", "bob")
+	p.AddFootnote("note").AddParagraph().AddInsertedText("note", "bob")
+	c, err := w.AddComment(r, r, "bob", "comment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AddParagraph().AddInsertedText("comment", "bob")
+	if n := len(w.Revisions()); n != 3 {
+		t.Fatal("expected 3 revisions but has", n)
+	}
+	if n := w.AcceptAllRevisions(); n != 3 || len(w.Revisions()) != 0 {
+		t.Fatal("expected 3 revisions accepted but has", n)
+	}
+
+	fr := &Run{InstrText: " PAGE ", file: w}
+	p.Children = append(p.Children, fr)
+	rv := p.TrackDeletion(fr, "bob")
+	if fr.InstrText != "" || fr.DelInstrText != " PAGE " {
+		t.Fatal("expected w:delInstrText")
+	}
+	var buf bytes.Buffer
+	_, err = marshaller{data: &w.Document}.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<w:delInstrText> PAGE </w:delInstrText>") {
+		t.Fatal("expected w:delInstrText in", buf.String())
+	}
+	if !w.RejectRevision(rv.ID) || fr.InstrText != " PAGE " || fr.DelInstrText != "" {
+		t.Fatal("expected w:instrText after rejecting deletion")
+	}
+}
@@ -37,7 +37,8 @@ type Run struct {
 
 	RunProperties *RunProperties `xml:"w:rPr,omitempty"`
 
-	InstrText string `xml:"w:instrText,omitempty"`
+	InstrText    string `xml:"w:instrText,omitempty"`
+	DelInstrText string `xml:"w:delInstrText,omitempty"` // DelInstrText is the deleted InstrText
 
 	Children []interface{}
 
@@ -97,7 +98,15 @@ func (r *Run) parse(d *xml.Decoder, tt xml.StartElement) (child interface{}, err
 		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 			return nil, err
 		}
-		r.InstrText = value
+		r.InstrText += value
+		return nil, nil
+	case "delInstrText":
+		var value string
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return nil, err
+		}
+		r.DelInstrText += value
 		return nil, nil
 	case "t":
 		var value Text
@@ -123,59 +132,91 @@ func (r *Run) parse(d *xml.Decoder, tt xml.StartElement) (child interface{}, err
 			return nil, err
 		}
 		child = &value
-	case "AlternateContent":
-		/*var value AlternateContent
-		value.file = r.file
+	case "footnoteReference", "endnoteReference":
+		var id int
+		id, err = getAttInt(tt.Attr, "id")
+		if err != nil {
+			return nil, err
+		}
+		if tt.Name.Local == "footnoteReference" {
+			child = &FootnoteReference{ID: id}
+		} else {
+			child = &EndnoteReference{ID: id}
+		}
+		err = d.Skip()
+		if err != nil {
+			return nil, err
+		}
+	case "commentReference":
+		var id int
+		id, err = getAttInt(tt.Attr, "id")
+		if err != nil {
+			return nil, err
+		}
+		child = &CommentReference{ID: id}
+		err = d.Skip()
+		if err != nil {
+			return nil, err
+		}
+	case "annotationRef":
+		child = &AnnotationRef{}
+	case "fldChar":
+		var value FieldChar
 		err = d.DecodeElement(&value, &tt)
 		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 			return nil, err
 		}
-		if value.Choice == nil {
-			return nil, nil
+		child = &value
+	case "delText":
+		var value DelText
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return nil, err
 		}
-		if value.Choice.Requires != "wps" && value.Choice.Requires != "wpc" && value.Choice.Requires != "wpg" {
-			return nil, nil
+		child = &value
+	case "footnoteRef":
+		child = &FootnoteRef{}
+	case "endnoteRef":
+		child = &EndnoteRef{}
+	case "separator":
+		child = &Separator{}
+	case "continuationSeparator":
+		child = &ContinuationSeparator{}
+	case "AlternateContent":
+		var value RawXML
+		err = d.DecodeElement(&value, &tt)
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return nil, err
 		}
-		child = &value*/
-	altcont:
-		for {
-			tok, err1 := d.Token()
-			if err1 == io.EOF {
-				break
-			}
-			if err1 != nil {
-				return nil, err1
+		child = &value
+		// use the supported choice if any, otherwise keep it as it is
+		for i, tok := range value.tokens {
+			ttt, ok := tok.(xml.StartElement)
+			if !ok || ttt.Name.Local != "Choice" {
+				continue
 			}
-
-			if ttt, ok := tok.(xml.StartElement); ok && ttt.Name.Local == "Choice" {
-				for _, attr := range ttt.Attr {
-					if attr.Name.Local == "Requires" {
-						if attr.Value == "wps" || attr.Value == "wpc" || attr.Value == "wpg" {
-							tok, err = d.Token() // go into choice
-							if err != nil {
-								return nil, err
-							}
-							if ttt, ok := tok.(xml.StartElement); ok {
-								child, err = r.parse(d, ttt)
-							}
-							break altcont
-						}
-						break
-					}
-				}
+			req := getAtt(ttt.Attr, "Requires")
+			if req != "wps" && req != "wpc" && req != "wpg" {
+				continue
 			}
-			if et, ok := tok.(xml.EndElement); ok {
-				if et.Name.Local == "AlternateContent" {
+			for j, tok := range value.tokens[i+1:] {
+				if _, ok := tok.(xml.EndElement); ok {
 					break
 				}
+				if ttt, ok := tok.(xml.StartElement); ok {
+					rd := tokenSliceReader(value.tokens[i+j+2:])
+					return r.parse(xml.NewTokenDecoder(&rd), ttt)
+				}
 			}
-			err = d.Skip() // skip unsupported tags
-			if err != nil {
-				return nil, err
-			}
+			break
 		}
 	default:
-		err = d.Skip() // skip unsupported tags
+		var value RawXML
+		err = d.DecodeElement(&value, &tt) // keep unsupported tags
+		if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+			return nil, err
+		}
+		child = &value
 	}
 	return
 }
@@ -200,7 +241,9 @@ func (r *Run) KeepElements(name ...string) {
 
 // RunProperties encapsulates visual properties of a run
 type RunProperties struct {
-	XMLName   xml.Name `xml:"w:rPr,omitempty"`
+	XMLName   xml.Name      `xml:"w:rPr,omitempty"`
+	Ins       *RevisionMark `xml:"w:ins,omitempty"` // only in the rPr of pPr
+	Del       *RevisionMark `xml:"w:del,omitempty"` // only in the rPr of pPr
 	Fonts     *RunFonts
 	Bold      *Bold
 	ICs       *struct{} `xml:"w:iCs,omitempty"`
@@ -217,10 +260,21 @@ type RunProperties struct {
 	Underline *Underline
 	VertAlign *VertAlign
 	Strike    *Strike
+	Vanish    *Vanish
+
+	Others []*RawXML // unsupported elements kept as is
+
+	Change *RunPropertiesChange
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (r *RunProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, r, r.Others)
 }
 
 // UnmarshalXML ...
 func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -231,6 +285,8 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		}
 
 		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
 			case "rFonts":
 				var value RunFonts
@@ -306,12 +362,35 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				var value Strike
 				value.Val = getAtt(tt.Attr, "val")
 				r.Strike = &value
+			case "vanish":
+				var value Vanish
+				value.Val = getAtt(tt.Attr, "val")
+				r.Vanish = &value
+			case "ins", "del":
+				var value RevisionMark
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				if tt.Name.Local == "ins" {
+					r.Ins = &value
+				} else {
+					r.Del = &value
+				}
+			case "rPrChange":
+				var value RunPropertiesChange
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				r.Change = &value
 			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				continue
+				r.Others = append(r.Others, value.placeAfter(after))
 			}
 		}
 	}
@@ -321,11 +400,16 @@ func (r *RunProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 
 // RunFonts specifies the fonts used in the text of a run.
 type RunFonts struct {
-	XMLName  xml.Name `xml:"w:rFonts,omitempty"`
-	ASCII    string   `xml:"w:ascii,attr,omitempty"`
-	EastAsia string   `xml:"w:eastAsia,attr,omitempty"`
-	HAnsi    string   `xml:"w:hAnsi,attr,omitempty"`
-	Hint     string   `xml:"w:hint,attr,omitempty"`
+	XMLName       xml.Name `xml:"w:rFonts,omitempty"`
+	ASCII         string   `xml:"w:ascii,attr,omitempty"`
+	EastAsia      string   `xml:"w:eastAsia,attr,omitempty"`
+	HAnsi         string   `xml:"w:hAnsi,attr,omitempty"`
+	CS            string   `xml:"w:cs,attr,omitempty"`
+	Hint          string   `xml:"w:hint,attr,omitempty"`
+	ASCIITheme    string   `xml:"w:asciiTheme,attr,omitempty"`
+	EastAsiaTheme string   `xml:"w:eastAsiaTheme,attr,omitempty"`
+	HAnsiTheme    string   `xml:"w:hAnsiTheme,attr,omitempty"`
+	CSTheme       string   `xml:"w:cstheme,attr,omitempty"`
 }
 
 // UnmarshalXML ...
@@ -338,8 +422,18 @@ func (f *RunFonts) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			f.EastAsia = attr.Value
 		case "hAnsi":
 			f.HAnsi = attr.Value
+		case "cs":
+			f.CS = attr.Value
 		case "hint":
 			f.Hint = attr.Value
+		case "asciiTheme":
+			f.ASCIITheme = attr.Value
+		case "eastAsiaTheme":
+			f.EastAsiaTheme = attr.Value
+		case "hAnsiTheme":
+			f.HAnsiTheme = attr.Value
+		case "cstheme":
+			f.CSTheme = attr.Value
 		}
 	}
 	// Consume the end element
@@ -24,19 +24,150 @@ import (
 )
 
 // SectPr show the properties of the document, like paper size
+//
+// The last <w:sectPr> in body describes the last section, while
+// the ones in <w:pPr> describe the sections end at those paragraphs.
 type SectPr struct {
-	XMLName xml.Name `xml:"w:sectPr,omitempty"` // properties of the document, including paper size
-	PgSz    *PgSz    `xml:"w:pgSz,omitempty"`
+	XMLName          xml.Name `xml:"w:sectPr,omitempty"` // properties of the document, including paper size
+	RsidR            string   `xml:"w:rsidR,attr,omitempty"`
+	RsidRPr          string   `xml:"w:rsidRPr,attr,omitempty"`
+	RsidDel          string   `xml:"w:rsidDel,attr,omitempty"`
+	RsidSect         string   `xml:"w:rsidSect,attr,omitempty"`
+	HeaderReferences []*HeaderReference
+	FooterReferences []*FooterReference
+	Type             *SectType
+	PgSz             *PgSz `xml:"w:pgSz,omitempty"`
+	PgMar            *PgMar
+	LnNumType        *LnNumType
+	PgNumType        *PgNumType
+	Cols             *Cols
+	VAlign           *WVerticalAlignment
+	TitlePg          *TitlePg
+	DocGrid          *DocGrid
+
+	Others []*RawXML // unsupported elements kept as is, like pgBorders and footnotePr
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (sect *SectPr) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, sect, sect.Others)
+}
+
+// SectType <w:type> shows how the section starts
+//
+//	w:val 属性的取值可以是以下之一：
+//		nextPage：下一页（默认）。
+//		continuous：连续。
+//		evenPage：偶数页。
+//		oddPage：奇数页。
+//		nextColumn：下一栏。
+type SectType struct {
+	XMLName xml.Name `xml:"w:type,omitempty"`
+	Val     string   `xml:"w:val,attr"`
 }
 
 // PgSz show the paper size
 type PgSz struct {
-	W xml.Attr `xml:"w:w,attr"` // width of paper
-	H xml.Attr `xml:"w:h,attr"` // high of paper
+	W      xml.Attr `xml:"w:w,attr"`      // width of paper
+	H      xml.Attr `xml:"w:h,attr"`      // high of paper
+	Orient xml.Attr `xml:"w:orient,attr"` // portrait or landscape
+}
+
+// PgMar <w:pgMar> is the page margins
+//
+// unit: twips (1/20 point)
+type PgMar struct {
+	XMLName xml.Name `xml:"w:pgMar,omitempty"`
+	Top     int      `xml:"w:top,attr"`
+	Right   int      `xml:"w:right,attr"`
+	Bottom  int      `xml:"w:bottom,attr"`
+	Left    int      `xml:"w:left,attr"`
+	Header  int      `xml:"w:header,attr"`
+	Footer  int      `xml:"w:footer,attr"`
+	Gutter  int      `xml:"w:gutter,attr"`
+}
+
+// LnNumType <w:lnNumType> shows the line numbers
+//
+//	w:restart 属性的取值可以是以下之一：
+//		newPage：每页重新编号（默认）。
+//		newSection：每节重新编号。
+//		continuous：连续编号。
+type LnNumType struct {
+	XMLName  xml.Name `xml:"w:lnNumType,omitempty"`
+	CountBy  int      `xml:"w:countBy,attr,omitempty"`
+	Start    int      `xml:"w:start,attr,omitempty"`
+	Distance int      `xml:"w:distance,attr,omitempty"`
+	Restart  string   `xml:"w:restart,attr,omitempty"`
+}
+
+// PgNumType <w:pgNumType> is the format of page numbers
+//
+//	w:fmt 属性常见的取值有：
+//		decimal：阿拉伯数字（默认）。
+//		upperRoman / lowerRoman：大写 / 小写罗马数字。
+//		upperLetter / lowerLetter：大写 / 小写字母。
+//		chineseCounting：中文数字。
+type PgNumType struct {
+	XMLName xml.Name `xml:"w:pgNumType,omitempty"`
+	Fmt     string   `xml:"w:fmt,attr,omitempty"`
+	Start   *int     `xml:"w:start,attr,omitempty"` // restart numbering from Start if not nil
+}
+
+// Cols <w:cols> is the text columns of the section
+type Cols struct {
+	XMLName    xml.Name `xml:"w:cols,omitempty"`
+	Num        int      `xml:"w:num,attr,omitempty"`
+	Space      int      `xml:"w:space,attr,omitempty"`
+	EqualWidth string   `xml:"w:equalWidth,attr,omitempty"` // 0 if Cols is set
+	Sep        string   `xml:"w:sep,attr,omitempty"`        // 1 to draw lines between columns
+	Cols       []*Col
+}
+
+// Col <w:col> is a column of unequal width
+type Col struct {
+	XMLName xml.Name `xml:"w:col,omitempty"`
+	W       int      `xml:"w:w,attr"`
+	Space   int      `xml:"w:space,attr,omitempty"`
+}
+
+// TitlePg <w:titlePg> enables the first page header and footer
+type TitlePg struct {
+	XMLName xml.Name `xml:"w:titlePg,omitempty"`
+	Val     string   `xml:"w:val,attr,omitempty"`
+}
+
+// DocGrid <w:docGrid> is the grid of lines and characters
+//
+//	w:type 属性的取值可以是以下之一：
+//		default：无网格。
+//		lines：只指定行网格。
+//		linesAndChars：指定行和字符网格。
+//		snapToChars：文字对齐字符网格。
+type DocGrid struct {
+	XMLName   xml.Name `xml:"w:docGrid,omitempty"`
+	Type      string   `xml:"w:type,attr,omitempty"`
+	LinePitch int      `xml:"w:linePitch,attr,omitempty"`
+	CharSpace int      `xml:"w:charSpace,attr,omitempty"`
 }
 
 // UnmarshalXML ...
-func (sect *SectPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+func (sect *SectPr) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "rsidR":
+			sect.RsidR = attr.Value
+		case "rsidRPr":
+			sect.RsidRPr = attr.Value
+		case "rsidDel":
+			sect.RsidDel = attr.Value
+		case "rsidSect":
+			sect.RsidSect = attr.Value
+		default:
+			// ignore other attributes
+		}
+	}
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -46,7 +177,21 @@ func (sect *SectPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 			return err
 		}
 		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
+			case "headerReference":
+				sect.HeaderReferences = append(sect.HeaderReferences, &HeaderReference{
+					Type: getAtt(tt.Attr, "type"),
+					ID:   getAtt(tt.Attr, "id"),
+				})
+			case "footerReference":
+				sect.FooterReferences = append(sect.FooterReferences, &FooterReference{
+					Type: getAtt(tt.Attr, "type"),
+					ID:   getAtt(tt.Attr, "id"),
+				})
+			case "type":
+				sect.Type = &SectType{Val: getAtt(tt.Attr, "val")}
 			case "pgSz":
 				var value PgSz
 				err = d.DecodeElement(&value, &tt)
@@ -54,11 +199,63 @@ func (sect *SectPr) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 					return err
 				}
 				sect.PgSz = &value
+				continue
+			case "pgMar":
+				var value PgMar
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sect.PgMar = &value
+				continue
+			case "lnNumType":
+				var value LnNumType
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sect.LnNumType = &value
+				continue
+			case "pgNumType":
+				var value PgNumType
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sect.PgNumType = &value
+				continue
+			case "cols":
+				var value Cols
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sect.Cols = &value
+				continue
+			case "vAlign":
+				sect.VAlign = &WVerticalAlignment{Val: getAtt(tt.Attr, "val")}
+			case "titlePg":
+				sect.TitlePg = &TitlePg{Val: getAtt(tt.Attr, "val")}
+			case "docGrid":
+				var value DocGrid
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sect.DocGrid = &value
+				continue
 			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
+				sect.Others = append(sect.Others, value.placeAfter(after))
+				continue
+			}
+			err = d.Skip() // skip the rest
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -75,6 +272,8 @@ func (pgsz *PgSz) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			pgsz.W = xml.Attr{Name: xml.Name{Local: "w:w"}, Value: attr.Value}
 		case "h":
 			pgsz.H = xml.Attr{Name: xml.Name{Local: "w:h"}, Value: attr.Value}
+		case "orient":
+			pgsz.Orient = xml.Attr{Name: xml.Name{Local: "w:orient"}, Value: attr.Value}
 		default:
 			// ignore other attributes now
 		}
@@ -83,3 +282,167 @@ func (pgsz *PgSz) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	_, err = d.Token()
 	return err
 }
+
+// UnmarshalXML ...
+func (m *PgMar) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		if attr.Value == "" {
+			continue
+		}
+		var v *int
+		switch attr.Name.Local {
+		case "top":
+			v = &m.Top
+		case "right":
+			v = &m.Right
+		case "bottom":
+			v = &m.Bottom
+		case "left":
+			v = &m.Left
+		case "header":
+			v = &m.Header
+		case "footer":
+			v = &m.Footer
+		case "gutter":
+			v = &m.Gutter
+		default:
+			continue // ignore other attributes
+		}
+		*v, err = GetInt(attr.Value)
+		if err != nil {
+			return
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// UnmarshalXML ...
+func (l *LnNumType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		if attr.Value == "" {
+			continue
+		}
+		switch attr.Name.Local {
+		case "countBy":
+			l.CountBy, err = GetInt(attr.Value)
+		case "start":
+			l.Start, err = GetInt(attr.Value)
+		case "distance":
+			l.Distance, err = GetInt(attr.Value)
+		case "restart":
+			l.Restart = attr.Value
+		default:
+			// ignore other attributes
+		}
+		if err != nil {
+			return
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// UnmarshalXML ...
+func (p *PgNumType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		if attr.Value == "" {
+			continue
+		}
+		switch attr.Name.Local {
+		case "fmt":
+			p.Fmt = attr.Value
+		case "start":
+			var v int
+			v, err = GetInt(attr.Value)
+			if err != nil {
+				return
+			}
+			p.Start = &v
+		default:
+			// ignore other attributes
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
+
+// UnmarshalXML ...
+func (c *Cols) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		if attr.Value == "" {
+			continue
+		}
+		switch attr.Name.Local {
+		case "num":
+			c.Num, err = GetInt(attr.Value)
+		case "space":
+			c.Space, err = GetInt(attr.Value)
+		case "equalWidth":
+			c.EqualWidth = attr.Value
+		case "sep":
+			c.Sep = attr.Value
+		default:
+			// ignore other attributes
+		}
+		if err != nil {
+			return
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok && tt.Name.Local == "col" {
+			var value Col
+			for _, attr := range tt.Attr {
+				if attr.Value == "" {
+					continue
+				}
+				switch attr.Name.Local {
+				case "w":
+					value.W, err = GetInt(attr.Value)
+				case "space":
+					value.Space, err = GetInt(attr.Value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			c.Cols = append(c.Cols, &value)
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML ...
+func (g *DocGrid) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
+	for _, attr := range start.Attr {
+		if attr.Value == "" {
+			continue
+		}
+		switch attr.Name.Local {
+		case "type":
+			g.Type = attr.Value
+		case "linePitch":
+			g.LinePitch, err = GetInt(attr.Value)
+		case "charSpace":
+			g.CharSpace, err = GetInt(attr.Value)
+		default:
+			// ignore other attributes
+		}
+		if err != nil {
+			return
+		}
+	}
+	// Consume the end element
+	_, err = d.Token()
+	return
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSectPrRoundTrip(t *testing.T) {
+	w := New().WithA4Page()
+	w.SectPr().WithMargins(1440, 1800, 1440, 1800).WithPageNumbering("lowerRoman", 0)
+	w.AddParagraph().AddText("first section")
+	w.AddSectionBreak("nextPage").
+		WithOrientation("landscape").
+		WithColumns(2, 425).
+		WithLineNumbering(5, 1, 360, "newSection").
+		WithVAlign("center").
+		WithTitlePage().
+		WithDocGrid("lines", 312, 0)
+	w.AddParagraph().AddText("second section")
+
+	var buf bytes.Buffer
+	_, err := marshaller{data: &w.Document}.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = New()
+	err = xml.Unmarshal(buf.Bytes(), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := w.Document.Body.Items
+	if len(items) != 4 {
+		t.Fatal("expected 4 body items but has", len(items))
+	}
+	first := items[1].(*Paragraph).Properties.SectPr
+	if first == nil {
+		t.Fatal("expected sectPr in the break paragraph")
+	}
+	if first.PgSz.W.Value != "11906" || first.PgMar.Left != 1800 || first.PgMar.Header != 720 {
+		t.Fatal("unexpected first section", first.PgSz, first.PgMar)
+	}
+	if first.PgNumType.Fmt != "lowerRoman" || first.PgNumType.Start == nil || *first.PgNumType.Start != 0 {
+		t.Fatal("unexpected page numbering", first.PgNumType)
+	}
+	last, ok := items[3].(*SectPr)
+	if !ok {
+		t.Fatal("expected sectPr at the end of body")
+	}
+	if last.Type.Val != "nextPage" {
+		t.Fatal("expected nextPage but has", last.Type.Val)
+	}
+	if last.PgSz.W.Value != "16838" || last.PgSz.H.Value != "11906" || last.PgSz.Orient.Value != "landscape" {
+		t.Fatal("unexpected page size", last.PgSz)
+	}
+	if last.Cols.Num != 2 || last.Cols.Space != 425 {
+		t.Fatal("unexpected cols", last.Cols)
+	}
+	if last.LnNumType.CountBy != 5 || last.LnNumType.Distance != 360 || last.LnNumType.Restart != "newSection" {
+		t.Fatal("unexpected line numbering", last.LnNumType)
+	}
+	if last.VAlign.Val != "center" || last.TitlePg == nil {
+		t.Fatal("unexpected vAlign or titlePg")
+	}
+	if last.DocGrid.Type != "lines" || last.DocGrid.LinePitch != 312 {
+		t.Fatal("unexpected docGrid", last.DocGrid)
+	}
+}
+
+func TestSectPrUnsupported(t *testing.T) {
+	w := New()
+	err := xml.Unmarshal(StringToBytes(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>`+
+		`<w:sectPr w:rsidR="00A1" w:rsidSect="00B2"><w:footnotePr><w:numFmt w:val="chicago"/></w:footnotePr>`+
+		`<w:pgSz w:w="11906" w:h="16838"/><w:pgBorders w:offsetFrom="page"><w:top w:val="single" w:sz="4" w:space="24" w:color="auto"/></w:pgBorders>`+
+		`<w:cols w:space="425"/><w:bidi/><w:docGrid w:type="lines" w:linePitch="312"/><w:printerSettings r:id="rId9"/></w:sectPr>`+
+		`</w:body></w:document>`), &w.Document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sect := w.AddParagraph().EndSection()
+	if len(sect.Others) != 4 || sect.Others[0] == w.lastSectPr().Others[0] {
+		t.Fatal("expected the unsupported elements deep-copied")
+	}
+
+	var buf bytes.Buffer
+	_, err = marshaller{data: &w.Document}.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<w:sectPr w:rsidR="00A1" w:rsidSect="00B2"><w:footnotePr><w:numFmt w:val="chicago"></w:numFmt></w:footnotePr>` +
+		`<w:pgSz w:w="11906" w:h="16838"></w:pgSz><w:pgBorders w:offsetFrom="page"><w:top w:val="single" w:sz="4" w:space="24" w:color="auto"></w:top></w:pgBorders>` +
+		`<w:cols w:space="425"></w:cols><w:bidi></w:bidi><w:docGrid w:type="lines" w:linePitch="312"></w:docGrid><w:printerSettings r:id="rId9"></w:printerSettings></w:sectPr>`
+	if n := strings.Count(buf.String(), expected); n != 2 {
+		t.Fatal("expected 2 sections kept as is but has", n, buf.String())
+	}
+}
@@ -0,0 +1,478 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+//nolint:revive,stylecheck
+const (
+	STYLE_TYPE_PARAGRAPH = "paragraph"
+	STYLE_TYPE_CHARACTER = "character"
+	STYLE_TYPE_TABLE     = "table"
+	STYLE_TYPE_NUMBERING = "numbering"
+)
+
+// Styles <w:styles> is word/styles.xml
+type Styles struct {
+	XMLName xml.Name `xml:"w:styles"`
+	XMLW    string   `xml:"xmlns:w,attr"`           // cannot be unmarshalled in
+	XMLR    string   `xml:"xmlns:r,attr,omitempty"` // cannot be unmarshalled in
+
+	DocDefaults  *DocDefaults
+	LatentStyles *RawXML
+	Styles       []*StyleDefinition
+
+	file *Docx
+}
+
+// UnmarshalXML ...
+func (s *Styles) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "docDefaults":
+				var value DocDefaults
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				s.DocDefaults = &value
+			case "latentStyles":
+				var value RawXML
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				s.LatentStyles = &value
+			case "style":
+				var value StyleDefinition
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				s.Styles = append(s.Styles, &value)
+			default:
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DocDefaults <w:docDefaults> is the default properties of all paragraphs and runs
+type DocDefaults struct {
+	XMLName    xml.Name `xml:"w:docDefaults"`
+	RPrDefault *RPrDefault
+	PPrDefault *PPrDefault
+}
+
+// UnmarshalXML ...
+func (dd *DocDefaults) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "rPrDefault":
+				var value RPrDefault
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				dd.RPrDefault = &value
+			case "pPrDefault":
+				var value PPrDefault
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				dd.PPrDefault = &value
+			default:
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// RPrDefault <w:rPrDefault>
+type RPrDefault struct {
+	XMLName       xml.Name `xml:"w:rPrDefault"`
+	RunProperties *RunProperties
+}
+
+// UnmarshalXML ...
+func (r *RPrDefault) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "rPr" {
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			var value RunProperties
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			r.RunProperties = &value
+		}
+	}
+	return nil
+}
+
+// PPrDefault <w:pPrDefault>
+type PPrDefault struct {
+	XMLName             xml.Name `xml:"w:pPrDefault"`
+	ParagraphProperties *ParagraphProperties
+}
+
+// UnmarshalXML ...
+func (p *PPrDefault) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			if tt.Name.Local != "pPr" {
+				err = d.Skip() // skip unsupported tags
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			var value ParagraphProperties
+			err = d.DecodeElement(&value, &tt)
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+			p.ParagraphProperties = &value
+		}
+	}
+	return nil
+}
+
+// StyleDefinition <w:style> defines a style which is referred by
+// Style, RunStyle and WTableStyle in document
+//
+//	w:type 属性的取值可以是以下之一：
+//		paragraph：段落样式。
+//		character：字符样式。
+//		table：表格样式。
+//		numbering：编号样式。
+type StyleDefinition struct {
+	XMLName     xml.Name `xml:"w:style"`
+	Type        string   `xml:"w:type,attr,omitempty"`
+	Default     string   `xml:"w:default,attr,omitempty"` // 1 if it is the default style of Type
+	CustomStyle string   `xml:"w:customStyle,attr,omitempty"`
+	StyleID     string   `xml:"w:styleId,attr"`
+
+	Name           *StyleName
+	BasedOn        *BasedOn
+	Next           *NextStyle
+	Link           *LinkStyle
+	UIPriority     *UIPriority
+	SemiHidden     *SemiHidden
+	UnhideWhenUsed *UnhideWhenUsed
+	QFormat        *QFormat
+
+	Others []*RawXML // unsupported elements kept as is
+
+	ParagraphProperties *ParagraphProperties
+	RunProperties       *RunProperties
+	TableProperties     *WTableProperties
+	TableRowProperties  *WTableRowProperties
+	TableCellProperties *WTableCellProperties
+
+	// TableStyleProperties are the conditional formatting of a table style
+	TableStyleProperties []*TableStyleProperties
+}
+
+// StyleName <w:name> is the name shown in Word
+type StyleName struct {
+	XMLName xml.Name `xml:"w:name,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// BasedOn <w:basedOn> is the styleId of the parent style
+type BasedOn struct {
+	XMLName xml.Name `xml:"w:basedOn,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// NextStyle <w:next> is the styleId of the style for the next new paragraph
+type NextStyle struct {
+	XMLName xml.Name `xml:"w:next,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LinkStyle <w:link> is the styleId of the linked paragraph or character style
+type LinkStyle struct {
+	XMLName xml.Name `xml:"w:link,omitempty"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// UIPriority <w:uiPriority> is the sort order in Word
+type UIPriority struct {
+	XMLName xml.Name `xml:"w:uiPriority,omitempty"`
+	Val     int      `xml:"w:val,attr"`
+}
+
+// SemiHidden <w:semiHidden> hides the style from the main UI
+type SemiHidden struct {
+	XMLName xml.Name `xml:"w:semiHidden,omitempty"`
+}
+
+// UnhideWhenUsed <w:unhideWhenUsed> shows the semi hidden style once used
+type UnhideWhenUsed struct {
+	XMLName xml.Name `xml:"w:unhideWhenUsed,omitempty"`
+}
+
+// QFormat <w:qFormat> shows the style in the style gallery
+type QFormat struct {
+	XMLName xml.Name `xml:"w:qFormat,omitempty"`
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (sd *StyleDefinition) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, sd, sd.Others)
+}
+
+// UnmarshalXML ...
+func (sd *StyleDefinition) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "type":
+			sd.Type = attr.Value
+		case "default":
+			sd.Default = attr.Value
+		case "customStyle":
+			sd.CustomStyle = attr.Value
+		case "styleId":
+			sd.StyleID = attr.Value
+		default:
+			// ignore other attributes
+		}
+	}
+	prev := "" // the previous sibling of the unsupported tags
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
+			switch tt.Name.Local {
+			case "name":
+				sd.Name = &StyleName{Val: getAtt(tt.Attr, "val")}
+			case "basedOn":
+				sd.BasedOn = &BasedOn{Val: getAtt(tt.Attr, "val")}
+			case "next":
+				sd.Next = &NextStyle{Val: getAtt(tt.Attr, "val")}
+			case "link":
+				sd.Link = &LinkStyle{Val: getAtt(tt.Attr, "val")}
+			case "uiPriority":
+				var value UIPriority
+				v := getAtt(tt.Attr, "val")
+				if v != "" {
+					value.Val, err = GetInt(v)
+					if err != nil {
+						return err
+					}
+				}
+				sd.UIPriority = &value
+			case "semiHidden":
+				sd.SemiHidden = &SemiHidden{}
+			case "unhideWhenUsed":
+				sd.UnhideWhenUsed = &UnhideWhenUsed{}
+			case "qFormat":
+				sd.QFormat = &QFormat{}
+			case "pPr":
+				var value ParagraphProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.ParagraphProperties = &value
+				continue
+			case "rPr":
+				var value RunProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.RunProperties = &value
+				continue
+			case "tblPr":
+				var value WTableProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.TableProperties = &value
+				continue
+			case "trPr":
+				var value WTableRowProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.TableRowProperties = &value
+				continue
+			case "tcPr":
+				var value WTableCellProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.TableCellProperties = &value
+				continue
+			case "tblStylePr":
+				var value TableStyleProperties
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.TableStyleProperties = append(sd.TableStyleProperties, &value)
+				continue
+			default:
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				sd.Others = append(sd.Others, value.placeAfter(after))
+				continue
+			}
+			err = d.Skip()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TableStyleProperties <w:tblStylePr> is the formatting of a part of the tables
+// in a table style, shown if enabled by the look of the table
+//
+//	w:type 属性的取值可以是以下之一：
+//		wholeTable：整个表格。
+//		firstRow：标题行。
+//		lastRow：汇总行。
+//		firstCol：第一列。
+//		lastCol：最后一列。
+//		band1Vert：奇数列。
+//		band2Vert：偶数列。
+//		band1Horz：奇数行。
+//		band2Horz：偶数行。
+//		neCell：右上单元格。
+//		nwCell：左上单元格。
+//		seCell：右下单元格。
+//		swCell：左下单元格。
+type TableStyleProperties struct {
+	XMLName xml.Name `xml:"w:tblStylePr"`
+	Type    string   `xml:"w:type,attr"`
+
+	ParagraphProperties *ParagraphProperties
+	RunProperties       *RunProperties
+	TableProperties     *WTableProperties
+	TableRowProperties  *WTableRowProperties
+	TableCellProperties *WTableCellProperties
+}
+
+// UnmarshalXML ...
+func (tp *TableStyleProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	tp.Type = getAtt(start.Attr, "type")
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if tt, ok := t.(xml.StartElement); ok {
+			switch tt.Name.Local {
+			case "pPr":
+				tp.ParagraphProperties = new(ParagraphProperties)
+				err = d.DecodeElement(tp.ParagraphProperties, &tt)
+			case "rPr":
+				tp.RunProperties = new(RunProperties)
+				err = d.DecodeElement(tp.RunProperties, &tt)
+			case "tblPr":
+				tp.TableProperties = new(WTableProperties)
+				err = d.DecodeElement(tp.TableProperties, &tt)
+			case "trPr":
+				tp.TableRowProperties = new(WTableRowProperties)
+				err = d.DecodeElement(tp.TableRowProperties, &tt)
+			case "tcPr":
+				tp.TableCellProperties = new(WTableCellProperties)
+				err = d.DecodeElement(tp.TableCellProperties, &tt)
+			default:
+				err = d.Skip() // skip unsupported tags
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+				return err
+			}
+		}
+	}
+	return nil
+}
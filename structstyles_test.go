@@ -0,0 +1,187 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestStyles(t *testing.T) {
+	w := New().WithDefaultTheme()
+	styles, err := w.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(styles.Styles) != 6 {
+		t.Fatal("expected 6 styles but has", len(styles.Styles))
+	}
+	normal := styles.Default(STYLE_TYPE_PARAGRAPH)
+	if normal == nil || normal.Name.Val != "Normal" || styles.StyleByName("Normal") != normal {
+		t.Fatal("expected Normal as the default paragraph style")
+	}
+	if styles.DocDefaults.RPrDefault.RunProperties.Fonts.ASCIITheme != "minorHAnsi" {
+		t.Fatal("expected theme fonts in docDefaults")
+	}
+
+	styles.AddStyle(STYLE_TYPE_PARAGRAPH, "Title1", "My Title").
+		WithBasedOn(normal.StyleID).WithNext(normal.StyleID).WithQFormat().
+		Bold().Size("32").Justification("center")
+	styles.AddStyle(STYLE_TYPE_CHARACTER, "Red", "My Red").Color("FF0000")
+	p := w.AddParagraph().Style("Title1")
+	r := p.AddText("title").Style("Red").Size("40")
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err = w.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(styles.Styles) != 8 {
+		t.Fatal("expected 8 styles but has", len(styles.Styles))
+	}
+	if styles.LatentStyles == nil {
+		t.Fatal("expected latentStyles to be kept")
+	}
+	p = w.Document.Body.Items[0].(*Paragraph)
+	r = p.Children[0].(*Run)
+
+	pp := styles.ResolveParagraphProperties(p)
+	if pp.Justification == nil || pp.Justification.Val != "center" {
+		t.Fatal("expected center from Title1 but has", pp.Justification)
+	}
+	if len(pp.Others) == 0 || pp.Others[0].Name().Local != "widowControl" {
+		t.Fatal("expected widowControl from Normal")
+	}
+	rp := styles.ResolveRunProperties(p, r)
+	if rp.Bold == nil {
+		t.Fatal("expected bold from Title1")
+	}
+	if rp.Color == nil || rp.Color.Val != "FF0000" {
+		t.Fatal("expected color from Red but has", rp.Color)
+	}
+	if rp.Size == nil || rp.Size.Val != "40" {
+		t.Fatal("expected size 40 from run but has", rp.Size)
+	}
+	if rp.Fonts == nil || rp.Fonts.ASCII != "Times New Roman" {
+		t.Fatal("expected font from Normal but has", rp.Fonts)
+	}
+	if rp.SizeCs == nil || rp.SizeCs.Val != "24" {
+		t.Fatal("expected szCs from docDefaults but has", rp.SizeCs)
+	}
+	if styles.Style("Red").RunProperties.Size != nil {
+		t.Fatal("styles should not be modified by resolving")
+	}
+}
+
+func TestResolveParagraphPropertiesOverlay(t *testing.T) {
+	w := New().WithDefaultTheme()
+	styles, err := w.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	normal := styles.Default(STYLE_TYPE_PARAGRAPH)
+	var pp ParagraphProperties
+	err = xml.Unmarshal([]byte(`<w:pPr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:widowControl w:val="0"/><w:rPr><w:b/></w:rPr><w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`+
+		`<w:pPrChange w:id="1" w:author="a"><w:pPr/></w:pPrChange></w:pPr>`), &pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normal.ParagraphProperties = &ParagraphProperties{Others: []*RawXML{pp.Others[0].clone()}, RunProperties: &RunProperties{Bold: &Bold{}}}
+	p := w.AddParagraph()
+	p.Properties = &pp
+
+	r := styles.ResolveParagraphProperties(p)
+	if len(r.Others) != 1 || r.Others[0] != pp.Others[0] {
+		t.Fatal("expected the widowControl of the paragraph only but has", len(r.Others))
+	}
+	if r.RunProperties != nil || r.SectPr != nil || r.Change != nil {
+		t.Fatal("expected no rPr, sectPr and pPrChange")
+	}
+}
+
+func TestTableStyles(t *testing.T) {
+	w := New().WithDefaultTheme()
+	styles, err := w.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	border := &WTableBorder{Val: "single", Size: 4, Color: "4472C4"}
+	sd := styles.AddStyle(STYLE_TYPE_TABLE, "Grid1", "My Grid").
+		TableBorders(&WTableBorders{Top: border, Left: border, Bottom: border, Right: border, InsideH: border, InsideV: border})
+	sd.Conditional("firstRow").Bold().Color("FFFFFF").Shade("clear", "auto", "4472C4")
+	sd.Conditional("band1Horz").Shade("clear", "auto", "D9E2F3")
+	sd.Conditional("firstRow").Italic()
+	if len(sd.TableStyleProperties) != 2 {
+		t.Fatal("expected 2 conditional formatting but has", len(sd.TableStyleProperties))
+	}
+	w.AddTable(3, 2).Style("Grid1", TableLook{FirstRow: true, FirstCol: true, NoVBand: true})
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err = w.Styles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd = styles.Style("Grid1")
+	if sd == nil || sd.TableProperties.TableBorders.Top.Color != "4472C4" {
+		t.Fatal("expected Grid1 with borders")
+	}
+	if len(sd.TableStyleProperties) != 2 {
+		t.Fatal("expected 2 conditional formatting but has", len(sd.TableStyleProperties))
+	}
+	first := sd.Conditional("firstRow")
+	if first.RunProperties.Bold == nil || first.RunProperties.Italic == nil || first.TableCellProperties.Shade.Fill != "4472C4" {
+		t.Fatal("expected firstRow formatting")
+	}
+	if sd.Conditional("band1Horz").TableCellProperties.Shade.Fill != "D9E2F3" {
+		t.Fatal("expected band1Horz formatting")
+	}
+	tab := w.Document.Body.Items[0].(*Table)
+	if tab.TableProperties.Style.Val != "Grid1" || tab.TableProperties.TableBorders != nil {
+		t.Fatal("expected Grid1 on the table")
+	}
+	if tab.TableProperties.Look.Val != "04A0" {
+		t.Fatal("expected look 04A0 but has", tab.TableProperties.Look.Val)
+	}
+	if look := tab.TableProperties.Look.Flags(); look != (TableLook{FirstRow: true, FirstCol: true, NoVBand: true}) {
+		t.Fatal("unexpected look", look)
+	}
+	if look := (&WTableLook{Val: "04A0"}).Flags(); look != (TableLook{FirstRow: true, FirstCol: true, NoVBand: true}) {
+		t.Fatal("unexpected legacy look", look)
+	}
+}
@@ -37,29 +37,57 @@ type Table struct {
 }
 
 func (t *Table) String() string {
-	if len(t.TableRows) == 0 || len(t.TableRows[0].TableCells) == 0 {
+	cols := t.gridColumns()
+	if len(t.TableRows) == 0 || cols == 0 {
 		return ""
 	}
 	sb := strings.Builder{}
 	sb.WriteString("| ")
-	for i := 0; i < len(t.TableRows[0].TableCells); i++ {
+	for i := 0; i < cols; i++ {
 		sb.WriteString(" :----: |")
 	}
 	for _, r := range t.TableRows {
 		sb.WriteString("\n|")
+		n := 0
 		for _, c := range r.TableCells {
-			if len(c.Paragraphs) > 0 && len(c.Paragraphs[0].Children) > 0 {
+			if s := c.String(); !c.vMergeContinued() && s != "" {
 				sb.WriteByte(' ')
-				sb.WriteString(c.Paragraphs[0].String())
+				sb.WriteString(s)
 			} else {
 				sb.WriteString("       ")
 			}
 			sb.WriteString(" |")
+			// the spanned columns are left empty
+			for i := 1; i < c.gridSpan(); i++ {
+				sb.WriteString("        |")
+			}
+			n += c.gridSpan()
+		}
+		for ; n < cols; n++ {
+			sb.WriteString("        |")
 		}
 	}
 	return sb.String()
 }
 
+// String is the first paragraph in c having children, or the first nested
+// table in one line, whichever comes first
+func (c *WTableCell) String() string {
+	for _, it := range c.Items {
+		switch o := it.(type) {
+		case *Paragraph:
+			if len(o.Children) > 0 {
+				return o.String()
+			}
+		case *Table:
+			if s := o.String(); s != "" {
+				return strings.ReplaceAll(s, "\n", "<br>")
+			}
+		}
+	}
+	return ""
+}
+
 // UnmarshalXML implements the xml.Unmarshaler interface.
 func (t *Table) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 	for {
@@ -113,10 +141,18 @@ type WTableProperties struct {
 	Justification *Justification `xml:"w:jc,omitempty"`
 	TableBorders  *WTableBorders `xml:"w:tblBorders"`
 	Look          *WTableLook
+
+	Others []*RawXML // unsupported elements kept as is
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (t *WTableProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, t, t.Others)
 }
 
 // UnmarshalXML implements the xml.Unmarshaler interface.
 func (t *WTableProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		token, err := d.Token()
 		if err == io.EOF {
@@ -126,6 +162,8 @@ func (t *WTableProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) erro
 			return err
 		}
 		if tt, ok := token.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
 			case "tblpPr":
 				t.Position = new(WTablePositioningProperties)
@@ -171,11 +209,12 @@ func (t *WTableProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) erro
 					return err
 				}
 			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				continue
+				t.Others = append(t.Others, value.placeAfter(after))
 			}
 		}
 	}
@@ -303,6 +342,17 @@ type WTableLook struct {
 	NoVBand  int      `xml:"w:noVBand,attr"`
 }
 
+// TableLook are the typed flags of WTableLook
+// that enable the conditional formatting of the table style
+type TableLook struct {
+	FirstRow bool // 标题行
+	LastRow  bool // 汇总行
+	FirstCol bool // 第一列
+	LastCol  bool // 最后一列
+	NoHBand  bool // 不显示镶边行
+	NoVBand  bool // 不显示镶边列
+}
+
 // UnmarshalXML ...
 func (t *WTableLook) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for _, attr := range start.Attr {
@@ -467,10 +517,18 @@ type WTableRowProperties struct {
 	XMLName        xml.Name `xml:"w:trPr,omitempty"`
 	TableRowHeight *WTableRowHeight
 	Justification  *Justification
+
+	Others []*RawXML // unsupported elements kept as is
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (t *WTableRowProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, t, t.Others)
 }
 
 // UnmarshalXML ...
 func (t *WTableRowProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		tok, err := d.Token()
 		if err == io.EOF {
@@ -481,6 +539,8 @@ func (t *WTableRowProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 		}
 
 		if tt, ok := tok.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
 			case "trHeight":
 				th := new(WTableRowHeight)
@@ -514,10 +574,12 @@ func (t *WTableRowProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) e
 					return err
 				}
 			default:
-				err = d.Skip()
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
+				t.Others = append(t.Others, value.placeAfter(after))
 			}
 		}
 	}
@@ -535,7 +597,9 @@ type WTableRowHeight struct {
 type WTableCell struct {
 	XMLName             xml.Name `xml:"w:tc,omitempty"`
 	TableCellProperties *WTableCellProperties
-	Paragraphs          []*Paragraph `xml:"w:p,omitempty"`
+
+	// Items are *Paragraph and *Table in the order of the cell
+	Items []interface{}
 
 	file *Docx
 }
@@ -560,7 +624,15 @@ func (c *WTableCell) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				c.Paragraphs = append(c.Paragraphs, &value)
+				c.Items = append(c.Items, &value)
+			case "tbl":
+				var value Table
+				value.file = c.file
+				err = d.DecodeElement(&value, &tt)
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
+					return err
+				}
+				c.Items = append(c.Items, &value)
 			case "tcPr":
 				var value WTableCellProperties
 				err = d.DecodeElement(&value, &tt)
@@ -589,10 +661,18 @@ type WTableCellProperties struct {
 	TableBorders   *WTableBorders `xml:"w:tcBorders"`
 	Shade          *Shade
 	VAlign         *WVerticalAlignment
+
+	Others []*RawXML // unsupported elements kept as is
+}
+
+// MarshalXML writes the unsupported elements back to their positions
+func (r *WTableCellProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalInOrder(e, start, r, r.Others)
 }
 
 // UnmarshalXML ...
 func (r *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	prev := "" // the previous sibling of the unsupported tags
 	for {
 		t, err := d.Token()
 		if err == io.EOF {
@@ -603,6 +683,8 @@ func (r *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 		}
 
 		if tt, ok := t.(xml.StartElement); ok {
+			after := prev
+			prev = tt.Name.Local
 			switch tt.Name.Local {
 			case "tcW":
 				r.TableCellWidth = new(WTableCellWidth)
@@ -644,11 +726,12 @@ func (r *WTableCellProperties) UnmarshalXML(d *xml.Decoder, _ xml.StartElement)
 				}
 				r.Shade = &value
 			default:
-				err = d.Skip() // skip unsupported tags
-				if err != nil {
+				var value RawXML
+				err = d.DecodeElement(&value, &tt) // keep unsupported tags
+				if err != nil && !strings.HasPrefix(err.Error(), "expected") {
 					return err
 				}
-				continue
+				r.Others = append(r.Others, value.placeAfter(after))
 			}
 		}
 	}
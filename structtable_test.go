@@ -21,10 +21,12 @@
 package docx
 
 import (
+	"bytes"
 	"encoding/xml"
 	"hash/crc64"
 	"io"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -101,3 +103,215 @@ func TestTableStructure(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestTableMergeCells(t *testing.T) {
+	w := New().WithDefaultTheme()
+	tab := w.AddTable(3, 3)
+	for i, tr := range tab.TableRows {
+		for j, tc := range tr.TableCells {
+			tc.AddParagraph().AddText(string(rune('a' + i*3 + j)))
+		}
+	}
+	err := tab.MergeCells(0, 0, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.TableRows[0].TableCells) != 2 || len(tab.TableRows[1].TableCells) != 2 {
+		t.Fatal("expected 2 cells in merged rows")
+	}
+	top := tab.Cell(1, 1)
+	if top != tab.TableRows[0].TableCells[0] || top.gridSpan() != 2 {
+		t.Fatal("expected the top left cell at (1, 1)")
+	}
+	if len(top.Paragraphs()) != 4 || top.Paragraphs()[3].String() != "e" {
+		t.Fatal("expected moved paragraphs but has", len(top.Paragraphs()))
+	}
+	if tab.Cell(1, 2).Paragraphs()[0].String() != "f" {
+		t.Fatal("expected f at (1, 2)")
+	}
+	if tab.MergeCells(1, 1, 2, 2) != ErrMergeCutsCells {
+		t.Fatal("expected ErrMergeCutsCells")
+	}
+	if tab.MergeCells(0, 0, 3, 0) != ErrCellOutOfRange {
+		t.Fatal("expected ErrCellOutOfRange")
+	}
+	s := tab.String()
+	if s != "|  :----: | :----: | :----: |\n| a |        | c |\n|        |        | f |\n| g | h | i |" {
+		t.Fatal("unexpected", s)
+	}
+	if text := w.PlainText(PlainTextOptions{CellSeparator: ","}); text != "a\nb\nd\ne,,c\n,,f\ng,h,i\n" {
+		t.Fatalf("unexpected %q", text)
+	}
+	err = tab.Unmerge(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range tab.TableRows {
+		if len(tr.TableCells) != 3 {
+			t.Fatal("expected 3 cells after unmerge")
+		}
+		for _, tc := range tr.TableCells {
+			if tc.gridSpan() != 1 || tc.vMergeContinued() || tc.TableCellProperties.VMerge != nil {
+				t.Fatal("expected no merge after unmerge")
+			}
+		}
+	}
+}
+
+func TestTableRowsAndColumns(t *testing.T) {
+	w := New().WithDefaultTheme()
+	tab := w.AddTableTwips([]int64{0, 0, 0}, []int64{1000, 1000, 1000})
+	for i, tr := range tab.TableRows {
+		for j, tc := range tr.TableCells {
+			tc.AddParagraph().Justification("center").AddText(string(rune('a' + i*3 + j)))
+		}
+	}
+	tab.TableRows[1].TableCells[0].Shade("clear", "auto", "E7E6E6")
+	err := tab.MergeCells(0, 2, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := tab.InsertRow(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.TableRows) != 4 || tab.TableRows[1] != tr {
+		t.Fatal("expected the inserted row at 1")
+	}
+	if tr.TableCells[0].TableCellProperties.Shade == nil || tr.TableCells[0].Paragraphs()[0].Properties.Justification == nil {
+		t.Fatal("expected the formatting of the template row")
+	}
+	if tr.TableCells[0].hasContent() || !tr.TableCells[2].vMergeContinued() {
+		t.Fatal("expected an empty row extending the merge")
+	}
+	tr, err = tab.CloneRow(3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.TableCells[1].Paragraphs()[0].String() != "h" || tr.TableCells[2].TableCellProperties.VMerge != nil {
+		t.Fatal("expected a copy of the last row without merge")
+	}
+	err = tab.DeleteRow(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top := tab.Cell(2, 2); top != tab.TableRows[0].TableCells[2] || top.Paragraphs()[0].String() != "c" {
+		t.Fatal("expected the merge content moved down")
+	}
+	err = tab.InsertColumn(1, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tab.TableGrid.GridCols) != 4 || tab.TableGrid.GridCols[1].W != 500 || len(tab.TableRows[0].TableCells) != 4 {
+		t.Fatal("expected a new column at 1")
+	}
+	if tab.TableRows[0].TableCells[1].TableCellProperties.Shade == nil {
+		t.Fatal("expected the formatting of the left cell")
+	}
+	err = tab.MergeCells(0, 0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tab.InsertColumn(1, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := tab.TableRows[0].TableCells[0]; c.gridSpan() != 3 || c.TableCellProperties.TableCellWidth.W != 2000 {
+		t.Fatal("expected the spanned cell widened")
+	}
+	err = tab.DeleteColumn(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tab.DeleteColumn(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := tab.TableRows[0].TableCells[0]; c.gridSpan() != 2 || c.TableCellProperties.TableCellWidth.W != 1000 {
+		t.Fatal("expected the spanned cell narrowed")
+	}
+	if len(tab.TableGrid.GridCols) != 3 || tab.gridColumns() != 3 {
+		t.Fatal("expected 3 columns but has", tab.gridColumns())
+	}
+	for _, tr := range tab.TableRows {
+		for _, tc := range tr.TableCells {
+			if tc.TableCellProperties.VMerge != nil {
+				t.Fatal("expected no merge left")
+			}
+		}
+	}
+	err = tab.InsertColumn(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := tab.TableGrid.GridCols[0]; g == nil || g.W != tab.TableGrid.GridCols[1].W {
+		t.Fatal("expected an auto column as wide as its neighbour")
+	}
+	if tab.DeleteRow(4) != ErrCellOutOfRange || tab.InsertColumn(5, 0) != ErrCellOutOfRange {
+		t.Fatal("expected ErrCellOutOfRange")
+	}
+}
+
+func TestNestedTable(t *testing.T) {
+	w := New().WithDefaultTheme()
+	tab := w.AddTable(1, 2)
+	tab.TableRows[0].TableCells[0].AddParagraph().AddText("outer")
+	cell := tab.TableRows[0].TableCells[1]
+	cell.AddParagraph().AddText("before")
+	nested := cell.AddTable(2, 2)
+	cell.AddParagraph()
+	nested.TableRows[0].TableCells[0].AddParagraph().AddText("inner")
+	p := nested.TableRows[1].TableCells[1].AddParagraph()
+	_, err := p.AddInlineDrawingFrom("testdata/fumiama.JPG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AddText("nil").Children = append(p.Children[1].(*Run).Children, &Drawing{})
+	w.Document.Body.DropDrawingOf("NilPicture")
+	if len(p.Children[1].(*Run).Children) != 1 {
+		t.Fatal("expected the nil picture in the nested table dropped")
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cell = w.Document.Body.Items[0].(*Table).TableRows[0].TableCells[1]
+	if len(cell.Items) != 3 || len(cell.Paragraphs()) != 2 {
+		t.Fatal("expected paragraph, table and paragraph in cell but has", len(cell.Items))
+	}
+	nested, ok := cell.Items[1].(*Table)
+	if !ok || nested.TableRows[0].TableCells[0].String() != "inner" {
+		t.Fatal("expected the nested table kept")
+	}
+	if s := w.Document.Body.Items[0].(*Table).String(); s != "|  :----: | :----: |\n| outer | before |" {
+		t.Fatal("unexpected", s)
+	}
+	if s := (&WTableCell{Items: []interface{}{&Paragraph{}, nested}}).String(); !strings.HasPrefix(s, "|  :----: | :----: |<br>| inner |        |<br>|") || !strings.HasSuffix(s, "nil |") {
+		t.Fatal("unexpected", s)
+	}
+	if text := w.PlainText(PlainTextOptions{}); text != "outer\tbefore\ninner\t\n\tnil\n\n" {
+		t.Fatalf("unexpected %q", text)
+	}
+
+	nw := New().WithDefaultTheme()
+	nw.AppendFile(w)
+	nested = nw.Document.Body.Items[0].(*Table).TableRows[0].TableCells[1].Items[1].(*Table)
+	d := nested.TableRows[1].TableCells[1].Paragraphs()[0].Children[0].(*Run).Children[0].(*Drawing)
+	if d.file != nw {
+		t.Fatal("expected the nested drawing copied")
+	}
+	embed, _, _ := d.picture()
+	target, err := nw.ReferTarget(embed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nw.Media(target[len("media/"):]) == nil {
+		t.Fatal("expected the media of the nested drawing copied")
+	}
+}
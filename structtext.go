@@ -63,6 +63,7 @@ func (tb *Tabs) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 type Tab struct {
 	XMLName  xml.Name `xml:"w:tab,omitempty"`
 	Val      string   `xml:"w:val,attr,omitempty"`
+	Leader   string   `xml:"w:leader,attr,omitempty"` // none, dot, hyphen, underscore ...
 	Position int      `xml:"w:pos,attr,omitempty"`
 }
 
@@ -73,6 +74,8 @@ func (t *Tab) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		switch attr.Name.Local {
 		case "val":
 			t.Val = attr.Value
+		case "leader":
+			t.Leader = attr.Value
 		case "pos":
 			if attr.Value == "" {
 				continue
@@ -170,6 +173,9 @@ func MergeSamePropRuns(r1, r2 *Run) bool {
 	for i := 1; i < rr1.NumField(); i++ {
 		x1 := rr1.Field(i)
 		x2 := rr2.Field(i)
+		if x1.Kind() != reflect.Ptr {
+			continue // Others are compared below
+		}
 		if x1.IsZero() && x2.IsZero() {
 			continue
 		}
@@ -190,6 +196,20 @@ func MergeSamePropRuns(r1, r2 *Run) bool {
 			}
 		}
 	}
+	return sameRawXMLs(r1.RunProperties.Others, r2.RunProperties.Others)
+}
+
+// sameRawXMLs reports whether the unsupported elements in a and b
+// are the same by their tokens
+func sameRawXMLs(a, b []*RawXML) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i].tokens, b[i].tokens) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -267,7 +287,8 @@ func (p *Paragraph) MergeText(canmerge RunMergeRule) (np Paragraph) {
 			if t.Text != "" {
 				r.Children = append(r.Children, t)
 			}
-			if prevrun != nil && canmerge(prevrun, &r) {
+			// the instruction of a field is kept in its own run
+			if prevrun != nil && prevrun.InstrText == "" && r.InstrText == "" && canmerge(prevrun, &r) {
 				var prevtext *Text
 				noappend := false
 				if len(prevrun.Children) == 0 {
@@ -292,7 +313,7 @@ func (p *Paragraph) MergeText(canmerge RunMergeRule) (np Paragraph) {
 							if noappend {
 								noappend = false
 							} else {
-								prevrun.Children = append(prevrun.Children, t)
+								prevrun.Children = append(prevrun.Children, prevtext)
 							}
 							prevtext = &Text{}
 						}
@@ -300,15 +321,30 @@ func (p *Paragraph) MergeText(canmerge RunMergeRule) (np Paragraph) {
 					}
 				}
 				if prevtext.Text != "" && !noappend {
-					prevrun.Children = append(prevrun.Children, t)
+					prevrun.Children = append(prevrun.Children, prevtext)
 				}
 			} else {
 				prevrun = &r
 				np.Children = append(np.Children, &r)
 			}
+		case *RawXML:
+			if o.Name().Local == "proofErr" {
+				continue // proofing marks are out of date after merging
+			}
+			np.Children = append(np.Children, o)
 		default:
 			np.Children = append(np.Children, o)
 		}
 	}
+	// the merged texts are new ones, which keep the spaces at their ends
+	for _, c := range np.Children {
+		if r, ok := c.(*Run); ok {
+			for _, x := range r.Children {
+				if t, ok := x.(*Text); ok && strings.TrimSpace(t.Text) != t.Text {
+					t.XMLSpace = "preserve"
+				}
+			}
+		}
+	}
 	return
 }
@@ -243,3 +243,21 @@ func TestMergeText(t *testing.T) {
 		t.Fatal("expected merged text [", namedpropmergdtext, "] but has [", sb.String(), "]")
 	}
 }
+
+func TestMergeSamePropRunsOthers(t *testing.T) {
+	p := Paragraph{}
+	err := xml.Unmarshal(StringToBytes(`<w:p xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`+
+		`<w:r><w:rPr><w:b/><w:lang w:val="en-US"/></w:rPr><w:t>a</w:t></w:r>`+
+		`<w:r><w:rPr><w:b/><w:lang w:val="en-US"/></w:rPr><w:t>b</w:t></w:r>`+
+		`<w:r><w:rPr><w:b/><w:lang w:val="zh-CN"/></w:rPr><w:t>c</w:t></w:r></w:p>`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	np := p.MergeText(MergeSamePropRuns)
+	if len(np.Children) != 2 {
+		t.Fatal("expected 2 runs but has", len(np.Children))
+	}
+	if s := np.Children[1].(*Run).RunProperties.Others[0].tokens[0].(xml.StartElement).Attr[0].Value; s != "zh-CN" {
+		t.Fatal("expected zh-CN kept but has", s)
+	}
+}
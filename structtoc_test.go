@@ -0,0 +1,123 @@
+/*
+   Copyright (c) 2020 gingfrederik
+   Copyright (c) 2021 Gonzalo Fernandez-Victorio
+   Copyright (c) 2021 Basement Crowd Ltd (https://www.basementcrowd.com)
+   Copyright (c) 2023 Fumiama Minamoto (源文雨)
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published
+   by the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestTableOfContents(t *testing.T) {
+	w := New().WithDefaultTheme()
+	toc, err := w.AddTableOfContents(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := toc.paras[0].String(); s != "No table of contents entries found." {
+		t.Fatal("unexpected empty toc", s)
+	}
+	for _, h := range []struct {
+		text  string
+		level int
+	}{{"Intro", 1}, {"Background", 2}, {"Details", 3}, {"Summary", 1}} {
+		_, err = w.AddHeading(h.text, h.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.AddParagraph().AddText("body of " + h.text)
+	}
+	err = toc.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	_, err = w.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = Parse(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv := w.HeadingLevel(w.Document.Body.Items[5].(*Paragraph)); lv != 2 {
+		t.Fatal("expected heading 2 but has", lv)
+	}
+	fields := w.Fields()
+	if len(fields) != 4 || fields[0].Type() != "TOC" || fields[0].Instruction() != `TOC \o "1-2" \h \z \u` {
+		t.Fatal("unexpected toc fields", len(fields))
+	}
+	for i, text := range []string{"Intro", "Background", "Summary"} {
+		p := w.Document.Body.Items[i].(*Paragraph)
+		j := 0
+		if i == 0 {
+			j = 3 // after the begin of TOC
+		}
+		h := p.Children[j].(*Hyperlink)
+		if paragraphText(p.Children) != text+"\t" {
+			t.Fatal("unexpected entry", paragraphText(p.Children))
+		}
+		if fields[i+1].Instruction() != "PAGEREF "+h.Anchor+` \h` {
+			t.Fatal("unexpected page ref", fields[i+1].Instruction())
+		}
+	}
+	if len(w.Bookmarks()) != 3 {
+		t.Fatal("expected 3 bookmarks but has", len(w.Bookmarks()))
+	}
+}
+
+func TestHeadingLevelKeepsStyles(t *testing.T) {
+	w := New().WithDefaultTheme()
+	w.AddParagraph().Style("Heading1").AddText("title")
+	if lv := w.HeadingLevel(w.Document.Body.Items[0].(*Paragraph)); lv != 1 {
+		t.Fatal("expected heading 1 but has", lv)
+	}
+	err := w.WriteHTML(io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.PlainText(PlainTextOptions{SkipHidden: true})
+	if w.styles != nil {
+		t.Fatal("expected the styles not to be written back after reading")
+	}
+	_, err = w.AddHeading("next", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.styles == nil || w.styles.Style("Heading2") == nil {
+		t.Fatal("expected the styles to be written back after adding a heading")
+	}
+
+	var sp Spacing
+	err = xml.Unmarshal([]byte(`<w:spacing xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" w:afterLines="0" w:after="0"/>`), &sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := xml.Marshal(&sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); s != `<w:spacing w:afterLines="0" w:after="0"></w:spacing>` {
+		t.Fatal("expected the explicit 0 kept but has", s)
+	}
+}
@@ -40,24 +40,18 @@ func (f *Docx) WithDefaultTheme() *Docx {
 
 // WithA3Page use A3 PageSize
 func (f *Docx) WithA3Page() *Docx {
-	sectpr := &SectPr{
-		PgSz: &PgSz{
-			W: xml.Attr{Name: xml.Name{Local: "w:w"}, Value: "16838"},
-			H: xml.Attr{Name: xml.Name{Local: "w:h"}, Value: "23811"},
-		},
+	f.SectPr().PgSz = &PgSz{
+		W: xml.Attr{Name: xml.Name{Local: "w:w"}, Value: "16838"},
+		H: xml.Attr{Name: xml.Name{Local: "w:h"}, Value: "23811"},
 	}
-	f.Document.Body.Items = append(f.Document.Body.Items, sectpr)
 	return f
 }
 
 // WithA4Page use A4 PageSize
 func (f *Docx) WithA4Page() *Docx {
-	sectpr := &SectPr{
-		PgSz: &PgSz{
-			W: xml.Attr{Name: xml.Name{Local: "w:w"}, Value: "11906"},
-			H: xml.Attr{Name: xml.Name{Local: "w:h"}, Value: "16838"},
-		},
+	f.SectPr().PgSz = &PgSz{
+		W: xml.Attr{Name: xml.Name{Local: "w:w"}, Value: "11906"},
+		H: xml.Attr{Name: xml.Name{Local: "w:h"}, Value: "16838"},
 	}
-	f.Document.Body.Items = append(f.Document.Body.Items, sectpr)
 	return f
 }
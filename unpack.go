@@ -27,6 +27,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // unpack receives a zip file (word documents are a zip with multiple xml inside)
@@ -35,6 +36,8 @@ import (
 //  1. Document
 //  2. Relationships
 //  3. Media
+//  4. Headers, footers, notes and comments
+//  5. Content types
 //
 // Then it stores all other files into tmpfslist for packing.
 func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
@@ -58,6 +61,13 @@ func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
 			}
 			continue
 		}
+		if f.Name == CONTENT_TYPES_NAME {
+			err = docx.parseContentTypes(f)
+			if err != nil {
+				return
+			}
+			continue
+		}
 		if strings.HasPrefix(f.Name, MEDIA_FOLDER) {
 			err = docx.parseMedia(f)
 			if err != nil {
@@ -68,8 +78,13 @@ func unpack(zipReader *zip.Reader) (docx *Docx, err error) {
 		// fill remaining files into tmpfslst
 		docx.tmpfslst = append(docx.tmpfslst, f.Name)
 	}
+	err = docx.parseParts(zipReader)
+	if err != nil {
+		return
+	}
 	//TODO: find last imageID
 	docx.imageID = 100000
+	docx.revID = uintptr(docx.seedRevisionID())
 	return
 }
 
@@ -90,6 +105,7 @@ func (f *Docx) parseDocument(file *zip.File) error {
 	f.Document.XMLWPS = XMLNS_WPS
 	f.Document.XMLWPC = XMLNS_WPC
 	f.Document.XMLWPG = XMLNS_WPG
+	f.Document.XMLW14 = XMLNS_W14
 	// f.Document.XMLWP14 = XMLNS_WP14
 	f.Document.XMLName.Space = XMLNS_W
 	f.Document.XMLName.Local = "document"
@@ -97,7 +113,7 @@ func (f *Docx) parseDocument(file *zip.File) error {
 	f.Document.Body.file = f
 	//TODO: find last docID
 	f.docID = 100000
-	err = xml.NewDecoder(zf).Decode(&f.Document)
+	err = newPartDecoder(zf).Decode(&f.Document)
 	return err
 }
 
@@ -129,6 +145,16 @@ func (f *Docx) parseDocRelation(file *zip.File) error {
 	return nil
 }
 
+// parseContentTypes processes [Content_Types].xml so that the overrides are kept
+func (f *Docx) parseContentTypes(file *zip.File) error {
+	zf, err := file.Open()
+	if err != nil {
+		return err
+	}
+	f.contentTypes, err = readContentTypes(zf)
+	return err
+}
+
 // parseMedia add the media into Docx struct
 func (f *Docx) parseMedia(file *zip.File) error {
 	name := file.Name[len(MEDIA_FOLDER):]
@@ -144,3 +170,214 @@ func (f *Docx) parseMedia(file *zip.File) error {
 	f.media = append(f.media, Media{Name: name, Data: data})
 	return zf.Close()
 }
+
+// parseParts parses the headers, footers, notes and comments referred in document
+// relationships and removes them from tmpfslst
+func (f *Docx) parseParts(zipReader *zip.Reader) error {
+	zfs := make(map[string]*zip.File, len(zipReader.File))
+	for _, zf := range zipReader.File {
+		zfs[zf.Name] = zf
+	}
+	parsed := make(map[string]struct{}, 16)
+	var ex *CommentsEx
+	exname := ""
+	for _, r := range f.docRelation.Relationship {
+		name := "word/" + r.Target
+		zf, ok := zfs[name]
+		if !ok {
+			continue
+		}
+		var v interface{}
+		switch r.Type {
+		case REL_HEADER:
+			h := f.newHeader(r.ID, r.Target)
+			f.headers = append(f.headers, h)
+			v = h
+		case REL_FOOTER:
+			ft := f.newFooter(r.ID, r.Target)
+			f.footers = append(f.footers, ft)
+			v = ft
+		case REL_FOOTNOTES:
+			f.footnotes = f.newFootnotes(r.Target)
+			f.footnotes.Notes = nil
+			v = f.footnotes
+		case REL_ENDNOTES:
+			f.endnotes = f.newEndnotes(r.Target)
+			f.endnotes.Notes = nil
+			v = f.endnotes
+		case REL_COMMENTS:
+			f.comments = f.newComments(r.Target)
+			v = f.comments
+		case REL_COMMENTS_EXTENDED:
+			ex = &CommentsEx{}
+			exname = r.Target
+			err := f.parsePart(zf, nil, ex)
+			if err != nil {
+				return err
+			}
+			continue
+		default:
+			continue
+		}
+		relsname := "word/_rels/" + r.Target + ".rels"
+		err := f.parsePart(zf, zfs[relsname], v)
+		if err != nil {
+			return err
+		}
+		parsed[name] = struct{}{}
+		parsed[relsname] = struct{}{}
+	}
+	if f.comments != nil && ex != nil {
+		f.comments.exname = exname
+		f.comments.applyEx(ex)
+		parsed["word/"+exname] = struct{}{}
+	}
+	if len(parsed) == 0 {
+		return nil
+	}
+	lst := f.tmpfslst[:0]
+	for _, name := range f.tmpfslst {
+		if _, ok := parsed[name]; !ok {
+			lst = append(lst, name)
+		}
+	}
+	f.tmpfslst = lst
+	return nil
+}
+
+// parsePart decodes a part other than document.xml into v. The relationships
+// of the part are kept in partRels with new IDs unique in the file,
+// so that the targets can be found by the IDs like those in the document.
+func (f *Docx) parsePart(file, relsfile *zip.File, v interface{}) error {
+	ids := make(map[string]string, 8)
+	if relsfile != nil {
+		rf, err := relsfile.Open()
+		if err != nil {
+			return err
+		}
+		var rels Relationships
+		err = xml.NewDecoder(rf).Decode(&rels)
+		_ = rf.Close()
+		if err != nil {
+			return err
+		}
+		for i, r := range rels.Relationship {
+			rels.Relationship[i].ID = "rId" + strconv.Itoa(int(atomic.AddUintptr(&f.rID, 1)))
+			ids[r.ID] = rels.Relationship[i].ID
+		}
+		rels.Xmlns = XMLNS_REL
+		if f.partRels == nil {
+			f.partRels = make(map[string]*Relationships, 8)
+		}
+		f.partRels[strings.TrimPrefix(file.Name, "word/")] = &rels
+	}
+	zf, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer zf.Close()
+	return xml.NewTokenDecoder(&relIDRewriter{d: newPartDecoder(zf), ids: ids}).Decode(v)
+}
+
+// relIDRewriter replaces the r:id, r:embed ... of a part
+// by the IDs unique in the file
+type relIDRewriter struct {
+	d   *xml.Decoder
+	ids map[string]string
+}
+
+// Token implements xml.TokenReader
+func (r *relIDRewriter) Token() (xml.Token, error) {
+	t, err := r.d.Token()
+	if err != nil {
+		return t, err
+	}
+	if se, ok := t.(xml.StartElement); ok {
+		for i, a := range se.Attr {
+			if a.Name.Space != XMLNS_R {
+				continue
+			}
+			if id, ok := r.ids[a.Value]; ok {
+				se.Attr[i].Value = id
+			}
+		}
+	}
+	return t, nil
+}
+
+// newPartDecoder returns a decoder of a part that keeps the prefixes
+// of the namespaces, see prefixKeeper
+func newPartDecoder(r io.Reader) *xml.Decoder {
+	return xml.NewTokenDecoder(&prefixKeeper{d: xml.NewDecoder(r)})
+}
+
+// prefixKeeper re-declares the namespaces used by an element on itself
+// if their prefixes are not the ones in knownPrefixes, so that RawXML
+// can write them back with the original prefixes. The prefixes listed
+// in mc:Requires and mc:Ignorable are always re-declared.
+type prefixKeeper struct {
+	d      *xml.Decoder
+	scopes []map[string]string // prefix -> namespace
+}
+
+// lookup returns the namespace of prefix p in the current scope
+func (k *prefixKeeper) lookup(p string) (string, bool) {
+	for i := len(k.scopes) - 1; i >= 0; i-- {
+		if space, ok := k.scopes[i][p]; ok {
+			return space, true
+		}
+	}
+	return "", false
+}
+
+// Token implements xml.TokenReader
+func (k *prefixKeeper) Token() (xml.Token, error) {
+	t, err := k.d.RawToken()
+	if err != nil {
+		return t, err
+	}
+	switch tt := t.(type) {
+	case xml.StartElement:
+		tt = tt.Copy()
+		scope := make(map[string]string, 2)
+		for _, a := range tt.Attr {
+			if a.Name.Space == "xmlns" {
+				scope[a.Name.Local] = a.Value
+			}
+		}
+		k.scopes = append(k.scopes, scope)
+		keep := func(p string, always bool) {
+			if p == "" || p == "xml" || p == "xmlns" {
+				return
+			}
+			if _, ok := scope[p]; ok {
+				return
+			}
+			space, ok := k.lookup(p)
+			if !ok || (!always && knownPrefixes[space] == p) {
+				return
+			}
+			scope[p] = space
+			tt.Attr = append(tt.Attr, xml.Attr{Name: xml.Name{Space: "xmlns", Local: p}, Value: space})
+		}
+		keep(tt.Name.Space, false)
+		for _, a := range tt.Attr {
+			keep(a.Name.Space, false)
+			p := a.Name.Space
+			if p == "" {
+				p = tt.Name.Space // mc:Choice Requires is not prefixed
+			}
+			if space, _ := k.lookup(p); space == XMLNS_MC && (a.Name.Local == "Requires" || a.Name.Local == "Ignorable") {
+				for _, p := range strings.Fields(a.Value) {
+					keep(p, true)
+				}
+			}
+		}
+		return tt, nil
+	case xml.EndElement:
+		if len(k.scopes) > 0 {
+			k.scopes = k.scopes[:len(k.scopes)-1]
+		}
+	}
+	return xml.CopyToken(t), nil
+}